@@ -23,6 +23,36 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// StorageTier is the kind of storage backing a replica.
+type StorageTier int32
+
+const (
+	// Unspecified tier, treated as SSD.
+	StorageTier_STORAGE_TIER_UNSPECIFIED StorageTier = 0
+	StorageTier_STORAGE_TIER_SSD         StorageTier = 1
+	StorageTier_STORAGE_TIER_HDD         StorageTier = 2
+)
+
+var StorageTier_name = map[int32]string{
+	0: "STORAGE_TIER_UNSPECIFIED",
+	1: "STORAGE_TIER_SSD",
+	2: "STORAGE_TIER_HDD",
+}
+
+var StorageTier_value = map[string]int32{
+	"STORAGE_TIER_UNSPECIFIED": 0,
+	"STORAGE_TIER_SSD":         1,
+	"STORAGE_TIER_HDD":         2,
+}
+
+func (x StorageTier) String() string {
+	return proto.EnumName(StorageTier_name, int32(x))
+}
+
+func (StorageTier) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_12f6fbc16db36c0a, []int{0}
+}
+
 // EntryType distinguishes files from directories in the namespace.
 type EntryType int32
 
@@ -49,7 +79,7 @@ func (x EntryType) String() string {
 }
 
 func (EntryType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_12f6fbc16db36c0a, []int{0}
+	return fileDescriptor_12f6fbc16db36c0a, []int{1}
 }
 
 // ObjectMeta contains metadata about an object.
@@ -109,6 +139,8 @@ type ReplicaInfo struct {
 	Addr string `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
 	// Zone of the blob server.
 	Zone string `protobuf:"bytes,2,opt,name=zone,proto3" json:"zone,omitempty"`
+	// Storage tier of the blob server.
+	Tier StorageTier `protobuf:"varint,3,opt,name=tier,proto3,enum=basaltpb.StorageTier" json:"tier,omitempty"`
 }
 
 func (m *ReplicaInfo) Reset()         { *m = ReplicaInfo{} }
@@ -283,6 +315,7 @@ func (m *ReplicationPolicy) XXX_DiscardUnknown() {
 var xxx_messageInfo_ReplicationPolicy proto.InternalMessageInfo
 
 func init() {
+	proto.RegisterEnum("basaltpb.StorageTier", StorageTier_name, StorageTier_value)
 	proto.RegisterEnum("basaltpb.EntryType", EntryType_name, EntryType_value)
 	proto.RegisterType((*ObjectMeta)(nil), "basaltpb.ObjectMeta")
 	proto.RegisterType((*ReplicaInfo)(nil), "basaltpb.ReplicaInfo")
@@ -294,44 +327,48 @@ func init() {
 func init() { proto.RegisterFile("basaltpb/common.proto", fileDescriptor_12f6fbc16db36c0a) }
 
 var fileDescriptor_12f6fbc16db36c0a = []byte{
-	// 591 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0x86, 0x63, 0x27, 0x4d, 0x9b, 0x49, 0x68, 0xc3, 0xb6, 0x45, 0x56, 0x01, 0x37, 0xcd, 0x01,
-	0x22, 0x84, 0x12, 0xa9, 0x15, 0xe2, 0x4c, 0x1b, 0x57, 0x32, 0x82, 0x36, 0x5a, 0x52, 0xa4, 0xf6,
-	0x62, 0xad, 0x77, 0x37, 0x8d, 0xc1, 0xf1, 0x5a, 0xf6, 0x82, 0x94, 0x3e, 0x02, 0x27, 0xc4, 0x0b,
-	0xf0, 0x3a, 0x3d, 0xf6, 0x88, 0x38, 0x54, 0xd0, 0xbe, 0x08, 0xf2, 0xda, 0x4e, 0x8c, 0x5a, 0x04,
-	0xdc, 0x26, 0xff, 0x7e, 0x33, 0xd9, 0xfd, 0xff, 0x31, 0xac, 0xbb, 0x24, 0x26, 0xbe, 0x0c, 0xdd,
-	0x1e, 0x15, 0x93, 0x89, 0x08, 0xba, 0x61, 0x24, 0xa4, 0x40, 0x4b, 0xb9, 0xbc, 0xb1, 0x76, 0x2a,
-	0x4e, 0x85, 0x12, 0x7b, 0x49, 0x95, 0x9e, 0xb7, 0xbf, 0xea, 0x00, 0x87, 0xee, 0x3b, 0x4e, 0xe5,
-	0x6b, 0x2e, 0x09, 0x7a, 0x00, 0xba, 0xc7, 0x0c, 0xad, 0xa5, 0x75, 0x1a, 0xbb, 0x8d, 0xf3, 0xcb,
-	0xcd, 0xd2, 0xf7, 0xcb, 0xcd, 0xca, 0xd1, 0x91, 0xdd, 0xc7, 0xba, 0xc7, 0x10, 0x82, 0x4a, 0xec,
-	0x9d, 0x71, 0x43, 0x6f, 0x69, 0x9d, 0x32, 0x56, 0x35, 0x7a, 0x0e, 0x4b, 0x11, 0x0f, 0x7d, 0x8f,
-	0x92, 0xd8, 0x28, 0xb7, 0xca, 0x9d, 0xfa, 0xf6, 0x7a, 0x37, 0xff, 0xcf, 0x2e, 0x4e, 0x4f, 0xec,
-	0x60, 0x24, 0x76, 0x2b, 0xc9, 0x38, 0x3c, 0x83, 0xd1, 0x26, 0xd4, 0x49, 0x44, 0xc7, 0xde, 0x47,
-	0xee, 0x44, 0x7c, 0x64, 0x54, 0x5a, 0x5a, 0xa7, 0x86, 0x21, 0x93, 0x30, 0x1f, 0xa1, 0x1d, 0xa8,
-	0x86, 0xc2, 0xf7, 0xe8, 0xd4, 0x58, 0x68, 0x69, 0x9d, 0xfa, 0xf6, 0xfd, 0x1b, 0x73, 0xa5, 0x27,
-	0x82, 0x81, 0x42, 0x70, 0x86, 0xa2, 0x0e, 0x34, 0x69, 0xc4, 0x89, 0xe4, 0xcc, 0x21, 0xd2, 0x09,
-	0x48, 0x20, 0x62, 0xa3, 0xaa, 0xae, 0xbb, 0x9c, 0xe9, 0x2f, 0xe4, 0x41, 0xa2, 0xa2, 0x47, 0xb0,
-	0x12, 0x73, 0xe2, 0x17, 0xc1, 0x45, 0x05, 0xde, 0x49, 0xe5, 0x8c, 0x6b, 0x3f, 0x83, 0x7a, 0xe1,
-	0x19, 0x89, 0x07, 0x84, 0xb1, 0x48, 0x79, 0x54, 0xc3, 0xaa, 0x4e, 0xb4, 0x33, 0x11, 0xa4, 0xbe,
-	0xd4, 0xb0, 0xaa, 0xdb, 0x9f, 0x74, 0x58, 0xee, 0x7b, 0x11, 0xa7, 0x52, 0x44, 0x53, 0x2b, 0x90,
-	0xd1, 0x34, 0xc1, 0x02, 0x32, 0xe1, 0x79, 0x6b, 0x52, 0xa3, 0xc7, 0x50, 0x91, 0xd3, 0x30, 0x6d,
-	0x5d, 0xde, 0x5e, 0x9d, 0x3f, 0x51, 0xb5, 0x0c, 0xa7, 0x21, 0xc7, 0x0a, 0xc8, 0x92, 0x29, 0xff,
-	0x25, 0x99, 0x4a, 0x21, 0x99, 0xdb, 0xac, 0x58, 0xf8, 0x57, 0x2b, 0xaa, 0xb7, 0x58, 0xf1, 0x5b,
-	0xd6, 0x8b, 0xff, 0x91, 0x75, 0x7b, 0x00, 0x35, 0xcc, 0x47, 0x3c, 0xe2, 0x01, 0xe5, 0xa8, 0x07,
-	0x0d, 0x96, 0x1b, 0xe3, 0xfc, 0x61, 0xdb, 0xea, 0x33, 0xc2, 0x66, 0x33, 0xdf, 0xf4, 0xb9, 0x6f,
-	0xed, 0x2f, 0x1a, 0xdc, 0xbd, 0xb1, 0x05, 0x68, 0x0b, 0x1a, 0x71, 0xcc, 0x9c, 0xd9, 0x25, 0x93,
-	0xd1, 0x0b, 0xb8, 0x1e, 0xc7, 0x0c, 0xe7, 0x6b, 0xb7, 0x05, 0x8d, 0x31, 0x2b, 0x20, 0x7a, 0x8a,
-	0x8c, 0xd9, 0x1c, 0x31, 0x60, 0x31, 0x5b, 0x43, 0xe5, 0xf7, 0x12, 0xce, 0x7f, 0xa2, 0x87, 0x00,
-	0xbe, 0xa0, 0xc4, 0x77, 0x54, 0xdc, 0xe9, 0xca, 0xd6, 0x94, 0x72, 0x22, 0x02, 0xfe, 0xe4, 0x2d,
-	0xd4, 0x66, 0xb1, 0xa1, 0x0d, 0xb8, 0x67, 0x1d, 0x0c, 0xf1, 0xb1, 0x33, 0x3c, 0x1e, 0x58, 0xce,
-	0xd1, 0xc1, 0x9b, 0x81, 0xb5, 0x67, 0xef, 0xdb, 0x56, 0xbf, 0x59, 0x42, 0xab, 0xb0, 0x52, 0x38,
-	0xdb, 0xb7, 0x5f, 0x59, 0x4d, 0x0d, 0x19, 0xb0, 0x56, 0x10, 0xfb, 0x36, 0xb6, 0xf6, 0x86, 0x87,
-	0xf8, 0xb8, 0xa9, 0xef, 0xbe, 0x3c, 0xff, 0x69, 0x96, 0xce, 0xaf, 0x4c, 0xed, 0xe2, 0xca, 0xd4,
-	0x7e, 0x5c, 0x99, 0xda, 0xe7, 0x6b, 0xb3, 0x74, 0x71, 0x6d, 0x96, 0xbe, 0x5d, 0x9b, 0xa5, 0x93,
-	0xa7, 0xa7, 0x9e, 0x1c, 0x7f, 0x70, 0xbb, 0x54, 0x4c, 0x7a, 0x54, 0xd0, 0xf7, 0x91, 0x20, 0x74,
-	0xcc, 0xdc, 0x5e, 0x9a, 0x0c, 0xf5, 0x3d, 0x1e, 0xc8, 0x5e, 0x1e, 0x93, 0x5b, 0x55, 0xdf, 0xfd,
-	0xce, 0xaf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x5b, 0x56, 0x65, 0xab, 0x30, 0x04, 0x00, 0x00,
+	// 648 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0xc1, 0x6e, 0xd3, 0x4c,
+	0x14, 0x85, 0x63, 0x27, 0x4d, 0x9b, 0x9b, 0xfc, 0x69, 0xfe, 0x69, 0x8b, 0xac, 0x52, 0xd2, 0x90,
+	0x05, 0x84, 0x0a, 0x25, 0x52, 0xbb, 0x60, 0xdd, 0x36, 0x2e, 0x18, 0x41, 0x1b, 0x4d, 0x5c, 0x50,
+	0xbb, 0x31, 0x93, 0xf1, 0x24, 0x31, 0x38, 0x9e, 0x68, 0x3c, 0x20, 0xa5, 0x8f, 0xc0, 0x0a, 0xf1,
+	0x02, 0xbc, 0x4e, 0x97, 0x5d, 0x22, 0x16, 0x15, 0xb4, 0x2f, 0x82, 0x3c, 0x76, 0x52, 0x97, 0x16,
+	0x01, 0xbb, 0xeb, 0x73, 0x3f, 0xdf, 0x19, 0x9f, 0x7b, 0x64, 0x58, 0xe9, 0x91, 0x90, 0xf8, 0x72,
+	0xdc, 0x6b, 0x51, 0x3e, 0x1a, 0xf1, 0xa0, 0x39, 0x16, 0x5c, 0x72, 0xb4, 0x30, 0x95, 0x57, 0x97,
+	0x07, 0x7c, 0xc0, 0x95, 0xd8, 0x8a, 0xaa, 0xb8, 0x5f, 0xff, 0xa2, 0x03, 0x1c, 0xf4, 0xde, 0x32,
+	0x2a, 0x5f, 0x32, 0x49, 0xd0, 0x1a, 0xe8, 0x9e, 0x6b, 0x68, 0x35, 0xad, 0x51, 0xda, 0x29, 0x9d,
+	0x9e, 0xaf, 0x67, 0xbe, 0x9d, 0xaf, 0xe7, 0x0e, 0x0f, 0xad, 0x36, 0xd6, 0x3d, 0x17, 0x21, 0xc8,
+	0x85, 0xde, 0x09, 0x33, 0xf4, 0x9a, 0xd6, 0xc8, 0x62, 0x55, 0xa3, 0x27, 0xb0, 0x20, 0xd8, 0xd8,
+	0xf7, 0x28, 0x09, 0x8d, 0x6c, 0x2d, 0xdb, 0x28, 0x6e, 0xae, 0x34, 0xa7, 0x67, 0x36, 0x71, 0xdc,
+	0xb1, 0x82, 0x3e, 0xdf, 0xc9, 0x45, 0xe3, 0xf0, 0x0c, 0x46, 0xeb, 0x50, 0x24, 0x82, 0x0e, 0xbd,
+	0x0f, 0xcc, 0x11, 0xac, 0x6f, 0xe4, 0x6a, 0x5a, 0xa3, 0x80, 0x21, 0x91, 0x30, 0xeb, 0xa3, 0x2d,
+	0xc8, 0x8f, 0xb9, 0xef, 0xd1, 0x89, 0x31, 0x57, 0xd3, 0x1a, 0xc5, 0xcd, 0xbb, 0x37, 0xe6, 0x4a,
+	0x8f, 0x07, 0x1d, 0x85, 0xe0, 0x04, 0x45, 0x0d, 0xa8, 0x50, 0xc1, 0x88, 0x64, 0xae, 0x43, 0xa4,
+	0x13, 0x90, 0x80, 0x87, 0x46, 0x5e, 0x5d, 0xb7, 0x9c, 0xe8, 0xdb, 0x72, 0x3f, 0x52, 0xd1, 0x03,
+	0x58, 0x0c, 0x19, 0xf1, 0xd3, 0xe0, 0xbc, 0x02, 0xff, 0x8b, 0xe5, 0x84, 0xab, 0xbf, 0x81, 0x62,
+	0xea, 0x33, 0x22, 0x0f, 0x88, 0xeb, 0x0a, 0xe5, 0x51, 0x01, 0xab, 0x3a, 0xd2, 0x4e, 0x78, 0x10,
+	0xfb, 0x52, 0xc0, 0xaa, 0x46, 0x8f, 0x20, 0x27, 0x3d, 0x26, 0x8c, 0x6c, 0x4d, 0x6b, 0x94, 0xd3,
+	0x9e, 0x74, 0x25, 0x17, 0x64, 0xc0, 0x6c, 0x8f, 0x09, 0xac, 0x90, 0xfa, 0x47, 0x1d, 0xca, 0x6d,
+	0x4f, 0x30, 0x2a, 0xb9, 0x98, 0x98, 0x81, 0x14, 0x93, 0x68, 0x62, 0x40, 0x46, 0x6c, 0x7a, 0x4a,
+	0x54, 0xa3, 0x87, 0x90, 0x93, 0x93, 0x71, 0x7c, 0x4a, 0x79, 0x73, 0xe9, 0x6a, 0xa2, 0x7a, 0xc5,
+	0x9e, 0x8c, 0x19, 0x56, 0x40, 0xb2, 0xc4, 0xec, 0x1f, 0x96, 0x98, 0x4b, 0x2d, 0xf1, 0x36, 0xd7,
+	0xe6, 0xfe, 0xd6, 0xb5, 0xfc, 0x2d, 0xae, 0x5d, 0x8b, 0xc5, 0xfc, 0x3f, 0xc4, 0xa2, 0xde, 0x81,
+	0x02, 0x66, 0x7d, 0x26, 0x58, 0x40, 0x19, 0x6a, 0x41, 0xc9, 0x9d, 0x1a, 0xe3, 0xfc, 0x26, 0x98,
+	0xc5, 0x19, 0x61, 0xb9, 0x33, 0xdf, 0xf4, 0x2b, 0xdf, 0xea, 0x9f, 0x35, 0xf8, 0xff, 0x46, 0x60,
+	0xd0, 0x7d, 0x28, 0x85, 0xa1, 0xeb, 0xcc, 0x2e, 0x19, 0x8d, 0x9e, 0xc3, 0xc5, 0x30, 0x74, 0x13,
+	0x36, 0x8c, 0x90, 0xa1, 0x9b, 0x42, 0xf4, 0x18, 0x19, 0xba, 0x57, 0x88, 0x01, 0xf3, 0x49, 0x62,
+	0x95, 0xdf, 0x0b, 0x78, 0xfa, 0x88, 0xee, 0x01, 0xf8, 0x9c, 0x12, 0xdf, 0x51, 0xc9, 0x88, 0xd3,
+	0x5d, 0x50, 0xca, 0x31, 0x0f, 0xd8, 0xc6, 0x6b, 0x28, 0xa6, 0x82, 0x80, 0xd6, 0xc0, 0xe8, 0xda,
+	0x07, 0x78, 0xfb, 0xa9, 0xe9, 0xd8, 0x96, 0x89, 0x9d, 0xc3, 0xfd, 0x6e, 0xc7, 0xdc, 0xb5, 0xf6,
+	0x2c, 0xb3, 0x5d, 0xc9, 0xa0, 0x65, 0xa8, 0x5c, 0xeb, 0x76, 0xbb, 0xed, 0x8a, 0x76, 0x43, 0x7d,
+	0xd6, 0x6e, 0x57, 0xf4, 0x8d, 0x57, 0x50, 0x98, 0xe5, 0x01, 0xad, 0xc2, 0x1d, 0x73, 0xdf, 0xc6,
+	0x47, 0x8e, 0x7d, 0xd4, 0x31, 0x7f, 0x19, 0xba, 0x04, 0x8b, 0xa9, 0xde, 0x9e, 0xf5, 0xc2, 0xac,
+	0x68, 0xc8, 0x80, 0xe5, 0x94, 0xd8, 0xb6, 0xb0, 0xb9, 0x6b, 0x1f, 0xe0, 0xa3, 0x8a, 0xbe, 0xf3,
+	0xfc, 0xf4, 0x47, 0x35, 0x73, 0x7a, 0x51, 0xd5, 0xce, 0x2e, 0xaa, 0xda, 0xf7, 0x8b, 0xaa, 0xf6,
+	0xe9, 0xb2, 0x9a, 0x39, 0xbb, 0xac, 0x66, 0xbe, 0x5e, 0x56, 0x33, 0xc7, 0x8f, 0x07, 0x9e, 0x1c,
+	0xbe, 0xef, 0x35, 0x29, 0x1f, 0xb5, 0x28, 0xa7, 0xef, 0x04, 0x27, 0x74, 0xe8, 0xf6, 0x5a, 0xf1,
+	0xca, 0xa9, 0xef, 0xb1, 0x40, 0xb6, 0xa6, 0xfb, 0xef, 0xe5, 0xd5, 0xbf, 0x67, 0xeb, 0xe7, 0x00,
+	0xb0, 0xdc, 0x94, 0x72, 0xb4, 0x04, 0x00, 0x00,
 }
 
 func (m *ObjectMeta) Marshal() (dAtA []byte, err error) {
@@ -435,6 +472,11 @@ func (m *ReplicaInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Tier != 0 {
+		i = encodeVarintCommon(dAtA, i, uint64(m.Tier))
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.Zone) > 0 {
 		i -= len(m.Zone)
 		copy(dAtA[i:], m.Zone)
@@ -675,6 +717,9 @@ func (m *ReplicaInfo) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovCommon(uint64(l))
 	}
+	if m.Tier != 0 {
+		n += 1 + sovCommon(uint64(m.Tier))
+	}
 	return n
 }
 
@@ -749,7 +794,7 @@ func (m *ReplicationPolicy) Size() (n int) {
 }
 
 func sovCommon(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+	return int((uint32(math_bits.Len64(x|1)+6) * 37) >> 8)
 }
 func sozCommon(x uint64) (n int) {
 	return sovCommon(uint64((x << 1) ^ uint64((int64(x) >> 63))))
@@ -1089,6 +1134,25 @@ func (m *ReplicaInfo) Unmarshal(dAtA []byte) error {
 			}
 			m.Zone = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tier", wireType)
+			}
+			m.Tier = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tier |= StorageTier(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCommon(dAtA[iNdEx:])
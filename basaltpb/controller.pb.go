@@ -27,6 +27,45 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// ReplicaFailureKind classifies a replica failure observed by a client.
+type ReplicaFailureKind int32
+
+const (
+	ReplicaFailureKind_REPLICA_FAILURE_KIND_UNSPECIFIED ReplicaFailureKind = 0
+	// The blob server could not be reached.
+	ReplicaFailureKind_REPLICA_FAILURE_KIND_UNREACHABLE ReplicaFailureKind = 1
+	// The blob server reported an I/O error.
+	ReplicaFailureKind_REPLICA_FAILURE_KIND_IO_ERROR ReplicaFailureKind = 2
+	// The replica holds data that disagrees with the other replicas.
+	ReplicaFailureKind_REPLICA_FAILURE_KIND_DIVERGENT ReplicaFailureKind = 3
+	// The replica fell too far behind the other replicas.
+	ReplicaFailureKind_REPLICA_FAILURE_KIND_SLOW ReplicaFailureKind = 4
+)
+
+var ReplicaFailureKind_name = map[int32]string{
+	0: "REPLICA_FAILURE_KIND_UNSPECIFIED",
+	1: "REPLICA_FAILURE_KIND_UNREACHABLE",
+	2: "REPLICA_FAILURE_KIND_IO_ERROR",
+	3: "REPLICA_FAILURE_KIND_DIVERGENT",
+	4: "REPLICA_FAILURE_KIND_SLOW",
+}
+
+var ReplicaFailureKind_value = map[string]int32{
+	"REPLICA_FAILURE_KIND_UNSPECIFIED": 0,
+	"REPLICA_FAILURE_KIND_UNREACHABLE": 1,
+	"REPLICA_FAILURE_KIND_IO_ERROR":    2,
+	"REPLICA_FAILURE_KIND_DIVERGENT":   3,
+	"REPLICA_FAILURE_KIND_SLOW":        4,
+}
+
+func (x ReplicaFailureKind) String() string {
+	return proto.EnumName(ReplicaFailureKind_name, int32(x))
+}
+
+func (ReplicaFailureKind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{0}
+}
+
 // TODO(cockroachlabs/basalt#1): Simplify MountRequest to take a directory_id
 // instead of cluster_id+store_id, deriving caller identity from the mTLS cert.
 type MountRequest struct {
@@ -38,6 +77,13 @@ type MountRequest struct {
 	ClusterId UUID `protobuf:"bytes,3,opt,name=cluster_id,json=clusterId,proto3,customtype=UUID" json:"cluster_id"`
 	// UUID of the store within the cluster.
 	StoreId UUID `protobuf:"bytes,4,opt,name=store_id,json=storeId,proto3,customtype=UUID" json:"store_id"`
+	// Identifies the request across retries, so that retrying a mount whose
+	// response was lost returns that mount rather than creating another.
+	RequestId UUID `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3,customtype=UUID" json:"request_id"`
+	// If true and the store is mounted by another instance, revoke its mount
+	// and mount the store in its place, for failing over a store from an
+	// unresponsive instance. The new mount's epoch fences the revoked mount.
+	Takeover bool `protobuf:"varint,6,opt,name=takeover,proto3" json:"takeover,omitempty"`
 }
 
 func (m *MountRequest) Reset()         { *m = MountRequest{} }
@@ -80,6 +126,19 @@ type MountResponse struct {
 	DirectoryId UUID `protobuf:"bytes,2,opt,name=directory_id,json=directoryId,proto3,customtype=UUID" json:"directory_id"`
 	// Write token for authenticating with blob servers.
 	WriteToken []byte `protobuf:"bytes,3,opt,name=write_token,json=writeToken,proto3" json:"write_token,omitempty"`
+	// Duration of the mount's lease in nanoseconds. The mount expires unless
+	// its lease is renewed with HeartbeatMount within this duration of the
+	// mount or the last heartbeat. 0 if the mount does not expire.
+	LeaseDurationNanos int64 `protobuf:"varint,4,opt,name=lease_duration_nanos,json=leaseDurationNanos,proto3" json:"lease_duration_nanos,omitempty"`
+	// Epoch of the mount. Each mount of a store directory has a greater epoch
+	// than every earlier mount of it.
+	Epoch uint64 `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	// Mount revoked by a takeover, if any.
+	PreviousMount *MountFence `protobuf:"bytes,6,opt,name=previous_mount,json=previousMount,proto3" json:"previous_mount,omitempty"`
+	// Unsealed objects in the store when the mount was taken over, which the
+	// revoked mount may have been writing and which should be recovered.
+	// Only populated by a takeover.
+	OpenObjects []*ObjectMeta `protobuf:"bytes,7,rep,name=open_objects,json=openObjects,proto3" json:"open_objects,omitempty"`
 }
 
 func (m *MountResponse) Reset()         { *m = MountResponse{} }
@@ -115,6 +174,52 @@ func (m *MountResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_MountResponse proto.InternalMessageInfo
 
+// MountFence identifies the mount under which a mutating request is made. A
+// request that modifies the namespace of a mounted store directory, or an
+// object in it, must carry the fence of that directory's current mount. The
+// controller rejects the request with FENCED if its fence is stale, i.e. the
+// mount has since been replaced by a mount with a newer epoch. The fence may
+// be omitted for directories that are not mounted.
+type MountFence struct {
+	// ID of the mount.
+	MountId UUID `protobuf:"bytes,1,opt,name=mount_id,json=mountId,proto3,customtype=UUID" json:"mount_id"`
+	// Epoch of the mount, as returned in its MountResponse.
+	Epoch uint64 `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+}
+
+func (m *MountFence) Reset()         { *m = MountFence{} }
+func (m *MountFence) String() string { return proto.CompactTextString(m) }
+func (*MountFence) ProtoMessage()    {}
+func (*MountFence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{2}
+}
+func (m *MountFence) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MountFence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MountFence.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MountFence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MountFence.Merge(m, src)
+}
+func (m *MountFence) XXX_Size() int {
+	return m.Size()
+}
+func (m *MountFence) XXX_DiscardUnknown() {
+	xxx_messageInfo_MountFence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MountFence proto.InternalMessageInfo
+
 type UnmountRequest struct {
 	MountId UUID `protobuf:"bytes,1,opt,name=mount_id,json=mountId,proto3,customtype=UUID" json:"mount_id"`
 }
@@ -123,7 +228,7 @@ func (m *UnmountRequest) Reset()         { *m = UnmountRequest{} }
 func (m *UnmountRequest) String() string { return proto.CompactTextString(m) }
 func (*UnmountRequest) ProtoMessage()    {}
 func (*UnmountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{2}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{3}
 }
 func (m *UnmountRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -159,7 +264,7 @@ func (m *UnmountResponse) Reset()         { *m = UnmountResponse{} }
 func (m *UnmountResponse) String() string { return proto.CompactTextString(m) }
 func (*UnmountResponse) ProtoMessage()    {}
 func (*UnmountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{3}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{4}
 }
 func (m *UnmountResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -188,6 +293,83 @@ func (m *UnmountResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UnmountResponse proto.InternalMessageInfo
 
+type HeartbeatMountRequest struct {
+	// Mount whose lease to renew.
+	MountId UUID `protobuf:"bytes,1,opt,name=mount_id,json=mountId,proto3,customtype=UUID" json:"mount_id"`
+}
+
+func (m *HeartbeatMountRequest) Reset()         { *m = HeartbeatMountRequest{} }
+func (m *HeartbeatMountRequest) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatMountRequest) ProtoMessage()    {}
+func (*HeartbeatMountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{5}
+}
+func (m *HeartbeatMountRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HeartbeatMountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HeartbeatMountRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HeartbeatMountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatMountRequest.Merge(m, src)
+}
+func (m *HeartbeatMountRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *HeartbeatMountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatMountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatMountRequest proto.InternalMessageInfo
+
+type HeartbeatMountResponse struct {
+	// Duration of the renewed lease in nanoseconds, measured from when the
+	// controller received the request.
+	LeaseDurationNanos int64 `protobuf:"varint,1,opt,name=lease_duration_nanos,json=leaseDurationNanos,proto3" json:"lease_duration_nanos,omitempty"`
+}
+
+func (m *HeartbeatMountResponse) Reset()         { *m = HeartbeatMountResponse{} }
+func (m *HeartbeatMountResponse) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatMountResponse) ProtoMessage()    {}
+func (*HeartbeatMountResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{6}
+}
+func (m *HeartbeatMountResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HeartbeatMountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HeartbeatMountResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HeartbeatMountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatMountResponse.Merge(m, src)
+}
+func (m *HeartbeatMountResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *HeartbeatMountResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatMountResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatMountResponse proto.InternalMessageInfo
+
 type CreateRequest struct {
 	// Directory in which to create the file.
 	DirectoryId UUID `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3,customtype=UUID" json:"directory_id"`
@@ -195,13 +377,19 @@ type CreateRequest struct {
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	// Replication policy for the new file.
 	Policy *ReplicationPolicy `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	// Identifies the request across retries, so that retrying a create that
+	// was applied returns the new file rather than failing with
+	// ALREADY_EXISTS.
+	RequestId UUID `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3,customtype=UUID" json:"request_id"`
+	// Fence of the mount of the directory the file is created in.
+	Fence *MountFence `protobuf:"bytes,5,opt,name=fence,proto3" json:"fence,omitempty"`
 }
 
 func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
 func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRequest) ProtoMessage()    {}
 func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{4}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{7}
 }
 func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -238,7 +426,7 @@ func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
 func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResponse) ProtoMessage()    {}
 func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{5}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{8}
 }
 func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -280,7 +468,7 @@ func (m *StatByPathRequest) Reset()         { *m = StatByPathRequest{} }
 func (m *StatByPathRequest) String() string { return proto.CompactTextString(m) }
 func (*StatByPathRequest) ProtoMessage()    {}
 func (*StatByPathRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{6}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{9}
 }
 func (m *StatByPathRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -322,7 +510,7 @@ func (m *StatByIDRequest) Reset()         { *m = StatByIDRequest{} }
 func (m *StatByIDRequest) String() string { return proto.CompactTextString(m) }
 func (*StatByIDRequest) ProtoMessage()    {}
 func (*StatByIDRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{7}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{10}
 }
 func (m *StatByIDRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -366,7 +554,7 @@ func (m *StatResponse) Reset()         { *m = StatResponse{} }
 func (m *StatResponse) String() string { return proto.CompactTextString(m) }
 func (*StatResponse) ProtoMessage()    {}
 func (*StatResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{8}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{11}
 }
 func (m *StatResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -400,13 +588,15 @@ type UnlinkRequest struct {
 	DirectoryId UUID `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3,customtype=UUID" json:"directory_id"`
 	// Name of the entry to unlink.
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Fence of the mount of the directory holding the entry.
+	Fence *MountFence `protobuf:"bytes,3,opt,name=fence,proto3" json:"fence,omitempty"`
 }
 
 func (m *UnlinkRequest) Reset()         { *m = UnlinkRequest{} }
 func (m *UnlinkRequest) String() string { return proto.CompactTextString(m) }
 func (*UnlinkRequest) ProtoMessage()    {}
 func (*UnlinkRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{9}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{12}
 }
 func (m *UnlinkRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -446,7 +636,7 @@ func (m *UnlinkResponse) Reset()         { *m = UnlinkResponse{} }
 func (m *UnlinkResponse) String() string { return proto.CompactTextString(m) }
 func (*UnlinkResponse) ProtoMessage()    {}
 func (*UnlinkResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{10}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{13}
 }
 func (m *UnlinkResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -480,13 +670,15 @@ type SealRequest struct {
 	ObjectId UUID `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3,customtype=UUID" json:"object_id"`
 	// Final size of the object.
 	Size_ int64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Fence of the mount of the directory the object was created in.
+	Fence *MountFence `protobuf:"bytes,3,opt,name=fence,proto3" json:"fence,omitempty"`
 }
 
 func (m *SealRequest) Reset()         { *m = SealRequest{} }
 func (m *SealRequest) String() string { return proto.CompactTextString(m) }
 func (*SealRequest) ProtoMessage()    {}
 func (*SealRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{11}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{14}
 }
 func (m *SealRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -522,7 +714,7 @@ func (m *SealResponse) Reset()         { *m = SealResponse{} }
 func (m *SealResponse) String() string { return proto.CompactTextString(m) }
 func (*SealResponse) ProtoMessage()    {}
 func (*SealResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{12}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{15}
 }
 func (m *SealResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -556,13 +748,15 @@ type MkdirRequest struct {
 	ParentId UUID `protobuf:"bytes,1,opt,name=parent_id,json=parentId,proto3,customtype=UUID" json:"parent_id"`
 	// Name for the new directory.
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Fence of the mount of the parent directory.
+	Fence *MountFence `protobuf:"bytes,3,opt,name=fence,proto3" json:"fence,omitempty"`
 }
 
 func (m *MkdirRequest) Reset()         { *m = MkdirRequest{} }
 func (m *MkdirRequest) String() string { return proto.CompactTextString(m) }
 func (*MkdirRequest) ProtoMessage()    {}
 func (*MkdirRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{13}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{16}
 }
 func (m *MkdirRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -600,7 +794,7 @@ func (m *MkdirResponse) Reset()         { *m = MkdirResponse{} }
 func (m *MkdirResponse) String() string { return proto.CompactTextString(m) }
 func (*MkdirResponse) ProtoMessage()    {}
 func (*MkdirResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{14}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{17}
 }
 func (m *MkdirResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -634,13 +828,15 @@ type RmdirRequest struct {
 	ParentId UUID `protobuf:"bytes,1,opt,name=parent_id,json=parentId,proto3,customtype=UUID" json:"parent_id"`
 	// Name of the directory to remove.
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Fence of the mount of the directory holding the directory to remove.
+	Fence *MountFence `protobuf:"bytes,3,opt,name=fence,proto3" json:"fence,omitempty"`
 }
 
 func (m *RmdirRequest) Reset()         { *m = RmdirRequest{} }
 func (m *RmdirRequest) String() string { return proto.CompactTextString(m) }
 func (*RmdirRequest) ProtoMessage()    {}
 func (*RmdirRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{15}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{18}
 }
 func (m *RmdirRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -676,7 +872,7 @@ func (m *RmdirResponse) Reset()         { *m = RmdirResponse{} }
 func (m *RmdirResponse) String() string { return proto.CompactTextString(m) }
 func (*RmdirResponse) ProtoMessage()    {}
 func (*RmdirResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{16}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{19}
 }
 func (m *RmdirResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -714,7 +910,7 @@ func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{17}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{20}
 }
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -750,13 +946,18 @@ type LinkRequest struct {
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	// Existing object to link to.
 	ObjectId UUID `protobuf:"bytes,3,opt,name=object_id,json=objectId,proto3,customtype=UUID" json:"object_id"`
+	// Identifies the request across retries, so that retrying a link that was
+	// applied succeeds rather than failing with ALREADY_EXISTS.
+	RequestId UUID `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3,customtype=UUID" json:"request_id"`
+	// Fence of the mount of the directory the link is created in.
+	Fence *MountFence `protobuf:"bytes,5,opt,name=fence,proto3" json:"fence,omitempty"`
 }
 
 func (m *LinkRequest) Reset()         { *m = LinkRequest{} }
 func (m *LinkRequest) String() string { return proto.CompactTextString(m) }
 func (*LinkRequest) ProtoMessage()    {}
 func (*LinkRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{18}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{21}
 }
 func (m *LinkRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -792,7 +993,7 @@ func (m *LinkResponse) Reset()         { *m = LinkResponse{} }
 func (m *LinkResponse) String() string { return proto.CompactTextString(m) }
 func (*LinkResponse) ProtoMessage()    {}
 func (*LinkResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{19}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{22}
 }
 func (m *LinkResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -828,13 +1029,18 @@ type RenameRequest struct {
 	OldName string `protobuf:"bytes,2,opt,name=old_name,json=oldName,proto3" json:"old_name,omitempty"`
 	// New name for the entry.
 	NewName string `protobuf:"bytes,3,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	// Identifies the request across retries, so that retrying a rename that
+	// was applied succeeds rather than failing with NOT_FOUND for old_name.
+	RequestId UUID `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3,customtype=UUID" json:"request_id"`
+	// Fence of the mount of the directory containing the entry.
+	Fence *MountFence `protobuf:"bytes,5,opt,name=fence,proto3" json:"fence,omitempty"`
 }
 
 func (m *RenameRequest) Reset()         { *m = RenameRequest{} }
 func (m *RenameRequest) String() string { return proto.CompactTextString(m) }
 func (*RenameRequest) ProtoMessage()    {}
 func (*RenameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{20}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{23}
 }
 func (m *RenameRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -870,7 +1076,7 @@ func (m *RenameResponse) Reset()         { *m = RenameResponse{} }
 func (m *RenameResponse) String() string { return proto.CompactTextString(m) }
 func (*RenameResponse) ProtoMessage()    {}
 func (*RenameResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{21}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{24}
 }
 func (m *RenameResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -918,7 +1124,7 @@ func (m *HeartbeatBlobServerRequest) Reset()         { *m = HeartbeatBlobServerR
 func (m *HeartbeatBlobServerRequest) String() string { return proto.CompactTextString(m) }
 func (*HeartbeatBlobServerRequest) ProtoMessage()    {}
 func (*HeartbeatBlobServerRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{22}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{25}
 }
 func (m *HeartbeatBlobServerRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -956,7 +1162,7 @@ func (m *HeartbeatBlobServerResponse) Reset()         { *m = HeartbeatBlobServer
 func (m *HeartbeatBlobServerResponse) String() string { return proto.CompactTextString(m) }
 func (*HeartbeatBlobServerResponse) ProtoMessage()    {}
 func (*HeartbeatBlobServerResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6ae6a4f3446e7bd8, []int{23}
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{26}
 }
 func (m *HeartbeatBlobServerResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -985,11 +1191,95 @@ func (m *HeartbeatBlobServerResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_HeartbeatBlobServerResponse proto.InternalMessageInfo
 
+type ReportFailureRequest struct {
+	// Object whose replica failed.
+	ObjectId UUID `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3,customtype=UUID" json:"object_id"`
+	// Addr of the blob server holding the failed replica.
+	Addr string `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	// Kind of failure observed.
+	Kind ReplicaFailureKind `protobuf:"varint,3,opt,name=kind,proto3,enum=basaltpb.ReplicaFailureKind" json:"kind,omitempty"`
+	// Human-readable description of the failure, for diagnostics.
+	Detail string `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+}
+
+func (m *ReportFailureRequest) Reset()         { *m = ReportFailureRequest{} }
+func (m *ReportFailureRequest) String() string { return proto.CompactTextString(m) }
+func (*ReportFailureRequest) ProtoMessage()    {}
+func (*ReportFailureRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{27}
+}
+func (m *ReportFailureRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReportFailureRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReportFailureRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ReportFailureRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportFailureRequest.Merge(m, src)
+}
+func (m *ReportFailureRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReportFailureRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportFailureRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportFailureRequest proto.InternalMessageInfo
+
+type ReportFailureResponse struct {
+}
+
+func (m *ReportFailureResponse) Reset()         { *m = ReportFailureResponse{} }
+func (m *ReportFailureResponse) String() string { return proto.CompactTextString(m) }
+func (*ReportFailureResponse) ProtoMessage()    {}
+func (*ReportFailureResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ae6a4f3446e7bd8, []int{28}
+}
+func (m *ReportFailureResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReportFailureResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReportFailureResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ReportFailureResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportFailureResponse.Merge(m, src)
+}
+func (m *ReportFailureResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReportFailureResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportFailureResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportFailureResponse proto.InternalMessageInfo
+
 func init() {
+	proto.RegisterEnum("basaltpb.ReplicaFailureKind", ReplicaFailureKind_name, ReplicaFailureKind_value)
 	proto.RegisterType((*MountRequest)(nil), "basaltpb.MountRequest")
 	proto.RegisterType((*MountResponse)(nil), "basaltpb.MountResponse")
+	proto.RegisterType((*MountFence)(nil), "basaltpb.MountFence")
 	proto.RegisterType((*UnmountRequest)(nil), "basaltpb.UnmountRequest")
 	proto.RegisterType((*UnmountResponse)(nil), "basaltpb.UnmountResponse")
+	proto.RegisterType((*HeartbeatMountRequest)(nil), "basaltpb.HeartbeatMountRequest")
+	proto.RegisterType((*HeartbeatMountResponse)(nil), "basaltpb.HeartbeatMountResponse")
 	proto.RegisterType((*CreateRequest)(nil), "basaltpb.CreateRequest")
 	proto.RegisterType((*CreateResponse)(nil), "basaltpb.CreateResponse")
 	proto.RegisterType((*StatByPathRequest)(nil), "basaltpb.StatByPathRequest")
@@ -1010,81 +1300,107 @@ func init() {
 	proto.RegisterType((*RenameResponse)(nil), "basaltpb.RenameResponse")
 	proto.RegisterType((*HeartbeatBlobServerRequest)(nil), "basaltpb.HeartbeatBlobServerRequest")
 	proto.RegisterType((*HeartbeatBlobServerResponse)(nil), "basaltpb.HeartbeatBlobServerResponse")
+	proto.RegisterType((*ReportFailureRequest)(nil), "basaltpb.ReportFailureRequest")
+	proto.RegisterType((*ReportFailureResponse)(nil), "basaltpb.ReportFailureResponse")
 }
 
 func init() { proto.RegisterFile("basaltpb/controller.proto", fileDescriptor_6ae6a4f3446e7bd8) }
 
 var fileDescriptor_6ae6a4f3446e7bd8 = []byte{
-	// 1103 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x4d, 0x4f, 0x1c, 0x47,
-	0x13, 0x66, 0x60, 0x58, 0x86, 0xda, 0x0f, 0x5e, 0xda, 0x36, 0x5e, 0x16, 0xbd, 0x8b, 0x33, 0x0a,
-	0x82, 0x28, 0x09, 0x1b, 0x61, 0x25, 0x32, 0xb1, 0x62, 0xc5, 0x6b, 0x22, 0x65, 0x22, 0x48, 0xac,
-	0x01, 0x2e, 0xbe, 0xac, 0x7a, 0xa6, 0x3b, 0x30, 0x61, 0x76, 0x7a, 0x33, 0xd3, 0x6b, 0xb4, 0x48,
-	0x39, 0x47, 0xc9, 0x29, 0x87, 0x5c, 0xf2, 0x3f, 0xf2, 0x03, 0x72, 0xe4, 0xe8, 0x63, 0x94, 0x83,
-	0x15, 0xc3, 0x1f, 0x89, 0xfa, 0x63, 0x76, 0x7b, 0x80, 0x8d, 0x03, 0xe2, 0xd6, 0x5b, 0x4f, 0x7d,
-	0x3c, 0x55, 0xd5, 0x5d, 0x35, 0x0b, 0x8b, 0x01, 0xce, 0x70, 0xcc, 0x7b, 0x41, 0x2b, 0x64, 0x09,
-	0x4f, 0x59, 0x1c, 0xd3, 0x74, 0xbd, 0x97, 0x32, 0xce, 0x90, 0x93, 0x43, 0x8d, 0x7b, 0x86, 0x52,
-	0xb7, 0xcb, 0x12, 0xa5, 0xd0, 0xb8, 0x7b, 0xc0, 0x0e, 0x98, 0x3c, 0xb6, 0xc4, 0x49, 0x49, 0xdd,
-	0xdf, 0x2c, 0xa8, 0xec, 0xb0, 0x7e, 0xc2, 0x7d, 0xfa, 0x7d, 0x9f, 0x66, 0x1c, 0x2d, 0x43, 0x39,
-	0x4a, 0x32, 0x8e, 0x93, 0x90, 0x76, 0x22, 0x52, 0xb7, 0x1e, 0x58, 0x6b, 0xb3, 0x3e, 0xe4, 0x22,
-	0x8f, 0x20, 0x04, 0xf6, 0x09, 0x4b, 0x68, 0x7d, 0x52, 0x22, 0xf2, 0x8c, 0xde, 0x07, 0x08, 0xe3,
-	0x7e, 0xc6, 0x69, 0x2a, 0x6c, 0xa6, 0x1e, 0x58, 0x6b, 0x95, 0x76, 0xe5, 0xf4, 0xf5, 0xf2, 0xc4,
-	0x5f, 0xaf, 0x97, 0xed, 0xfd, 0x7d, 0x6f, 0xcb, 0x9f, 0xd5, 0xb8, 0x47, 0xd0, 0x2a, 0x38, 0x19,
-	0x67, 0xa9, 0x74, 0x6f, 0x5f, 0xa1, 0x3a, 0x23, 0x51, 0x8f, 0xb8, 0x3f, 0x5b, 0x50, 0xd5, 0xdc,
-	0xb2, 0x1e, 0x4b, 0x32, 0x2a, 0x4c, 0xbb, 0x42, 0x90, 0x33, 0xbb, 0x64, 0x2a, 0x51, 0x8f, 0xa0,
-	0x16, 0x54, 0x48, 0x94, 0xd2, 0x90, 0xb3, 0x74, 0x20, 0x94, 0x27, 0xaf, 0x50, 0x2e, 0x0f, 0x35,
-	0x3c, 0x22, 0xd2, 0x3e, 0x4e, 0x23, 0x4e, 0x3b, 0x9c, 0x1d, 0xd1, 0x44, 0xa5, 0xe0, 0x83, 0x14,
-	0xed, 0x09, 0x89, 0xbb, 0x09, 0xb5, 0xfd, 0xa4, 0x6b, 0x56, 0xea, 0xbf, 0x92, 0x71, 0xe7, 0x61,
-	0x6e, 0x68, 0xaa, 0x12, 0x71, 0x7f, 0xb2, 0xa0, 0xfa, 0x2c, 0xa5, 0x98, 0xd3, 0xdc, 0xdb, 0x45,
-	0xc6, 0xd6, 0xdb, 0x18, 0x23, 0xb0, 0x13, 0xdc, 0x1d, 0xf6, 0x41, 0x9c, 0xd1, 0x43, 0x28, 0xf5,
-	0x58, 0x1c, 0x85, 0x03, 0x99, 0x40, 0x79, 0x63, 0x69, 0x3d, 0xbf, 0x0b, 0xeb, 0x3e, 0xed, 0xc5,
-	0x51, 0x88, 0x79, 0xc4, 0x92, 0xe7, 0x52, 0xc5, 0xd7, 0xaa, 0xee, 0xa7, 0x50, 0xcb, 0xa9, 0xe8,
-	0x32, 0xaf, 0x81, 0xdd, 0xa5, 0x1c, 0x4b, 0x0e, 0xe5, 0x8d, 0xbb, 0x23, 0x27, 0xdf, 0x04, 0xdf,
-	0xd1, 0x90, 0xef, 0x50, 0x8e, 0x7d, 0xa9, 0xe1, 0xfe, 0x68, 0xc1, 0xfc, 0x2e, 0xc7, 0xbc, 0x3d,
-	0x78, 0x8e, 0xf9, 0xe1, 0xad, 0xe6, 0xf2, 0x21, 0xa0, 0x28, 0x09, 0xe3, 0x3e, 0xa1, 0x9d, 0x94,
-	0x7e, 0x4b, 0x53, 0x9a, 0x84, 0x34, 0x93, 0x79, 0x39, 0xfe, 0xbc, 0x46, 0xfc, 0x21, 0xe0, 0xfe,
-	0x6a, 0xc1, 0x9c, 0x62, 0xe2, 0x6d, 0xe5, 0x3c, 0xde, 0x83, 0x59, 0x26, 0x19, 0x8f, 0x23, 0xe1,
-	0x28, 0xd8, 0x23, 0x63, 0xa2, 0x4d, 0x8e, 0x89, 0x86, 0x56, 0x61, 0x2e, 0x57, 0x3f, 0x61, 0xdd,
-	0x20, 0x1a, 0x32, 0xab, 0x69, 0xf1, 0x0b, 0x25, 0x75, 0x7f, 0xb7, 0xa0, 0x22, 0x68, 0x5d, 0xbf,
-	0xb6, 0x68, 0x15, 0x6c, 0x3e, 0xe8, 0xa9, 0xa2, 0xd4, 0x36, 0xee, 0x8c, 0x34, 0xbf, 0x48, 0x78,
-	0x3a, 0xd8, 0x1b, 0xf4, 0xa8, 0x2f, 0x15, 0xd0, 0x26, 0x40, 0xa1, 0x42, 0x53, 0x6b, 0x65, 0x53,
-	0x7d, 0x48, 0xbb, 0x6d, 0x8b, 0xe4, 0x7d, 0x43, 0x19, 0x2d, 0x40, 0x49, 0xf1, 0x97, 0x2f, 0xd1,
-	0xf1, 0xf5, 0x2f, 0x77, 0x0f, 0xaa, 0xfb, 0x49, 0x1c, 0x25, 0x47, 0xb7, 0xd9, 0x52, 0x37, 0x10,
-	0x6f, 0x48, 0x79, 0xd5, 0xd5, 0xb8, 0x46, 0x87, 0x56, 0xa0, 0xa6, 0x55, 0x09, 0x8d, 0x29, 0xa7,
-	0x44, 0x77, 0xa7, 0xaa, 0xa4, 0x5b, 0x4a, 0xe8, 0x6e, 0x43, 0x79, 0x97, 0xe2, 0xf8, 0x06, 0x57,
-	0x00, 0x81, 0x9d, 0x45, 0x27, 0x8a, 0xf1, 0x94, 0x2f, 0xcf, 0x6e, 0x0d, 0x2a, 0xca, 0x9b, 0x7e,
-	0xb7, 0x3b, 0x50, 0xd9, 0x39, 0x22, 0x51, 0x6a, 0xb8, 0xef, 0xe1, 0x94, 0x8e, 0x1f, 0x02, 0x8e,
-	0x82, 0xc7, 0x14, 0xe4, 0x73, 0xa8, 0x6a, 0x77, 0xba, 0x1e, 0xd7, 0x2d, 0xb3, 0x20, 0xe4, 0x77,
-	0x6f, 0x8f, 0xd0, 0x1c, 0x54, 0xb5, 0x3b, 0x9d, 0xf0, 0x13, 0x28, 0x6f, 0x47, 0x19, 0xbf, 0xe9,
-	0x35, 0x70, 0x7f, 0x10, 0xf6, 0xb7, 0x7b, 0x8d, 0x8a, 0x3d, 0x9d, 0xfa, 0xb7, 0x9e, 0x8a, 0xfe,
-	0x6d, 0x1b, 0xf7, 0xcd, 0x7d, 0x09, 0x55, 0x9f, 0x0a, 0x27, 0x37, 0x26, 0xb4, 0x08, 0x0e, 0x8b,
-	0x49, 0xc7, 0x20, 0x35, 0xc3, 0x62, 0xf2, 0xb5, 0xe0, 0xb5, 0x08, 0x4e, 0x42, 0x8f, 0x15, 0x34,
-	0xa5, 0xa0, 0x84, 0x1e, 0x0b, 0xc8, 0xfd, 0x1f, 0xd4, 0xf2, 0xb8, 0x9a, 0xc9, 0x1b, 0x0b, 0x1a,
-	0x5f, 0x52, 0x9c, 0xf2, 0x80, 0x62, 0xde, 0x8e, 0x59, 0xb0, 0x4b, 0xd3, 0x97, 0xd4, 0xec, 0x63,
-	0x26, 0x05, 0x63, 0xfb, 0xa8, 0x60, 0x8f, 0xa0, 0x77, 0xa0, 0xa2, 0xbf, 0x06, 0x3a, 0x98, 0x90,
-	0x54, 0xb3, 0x2a, 0x6b, 0xd9, 0x53, 0x42, 0x52, 0xb4, 0x04, 0xb3, 0x04, 0x73, 0xac, 0x70, 0x45,
-	0xcd, 0x11, 0x02, 0x09, 0xe6, 0x0b, 0xdd, 0x36, 0x16, 0xfa, 0x0a, 0xd4, 0x42, 0xdc, 0xc3, 0x61,
-	0xc4, 0x07, 0x9d, 0x60, 0xc0, 0x69, 0x56, 0x9f, 0x96, 0xaf, 0xa2, 0x9a, 0x4b, 0xdb, 0x42, 0x88,
-	0xfe, 0x0f, 0xd0, 0xcf, 0x28, 0xd1, 0x2a, 0x25, 0xa9, 0x32, 0x2b, 0x24, 0x12, 0x76, 0x3f, 0x81,
-	0xa5, 0x2b, 0x53, 0xd4, 0x97, 0xfd, 0x3e, 0xcc, 0x90, 0x28, 0x3b, 0xca, 0x33, 0x9c, 0xf6, 0x4b,
-	0xe2, 0xa7, 0x47, 0x36, 0xfe, 0x28, 0x01, 0x3c, 0x1b, 0x7e, 0xe0, 0xa0, 0x47, 0x30, 0x2d, 0x3f,
-	0x03, 0xd0, 0xc2, 0x68, 0xa8, 0x99, 0xdf, 0x2c, 0x8d, 0xfb, 0x97, 0xe4, 0x3a, 0xc2, 0x13, 0x98,
-	0xd1, 0x9b, 0x17, 0xd5, 0x47, 0x3a, 0xc5, 0x3d, 0xde, 0x58, 0xbc, 0x02, 0xd1, 0xf6, 0x8f, 0xa1,
-	0xa4, 0x56, 0x23, 0x32, 0x42, 0x14, 0xf6, 0x76, 0xa3, 0x7e, 0x19, 0xd0, 0xc6, 0x4f, 0x01, 0x46,
-	0xab, 0x11, 0x19, 0xab, 0xf8, 0xd2, 0xc2, 0x6c, 0x2c, 0x14, 0xc1, 0xa1, 0x8b, 0xcf, 0xc0, 0xc9,
-	0x77, 0x1a, 0x5a, 0xbc, 0xe8, 0x60, 0xb8, 0xe7, 0xc6, 0x9a, 0x3f, 0x86, 0x92, 0x9a, 0xb7, 0x26,
-	0xfd, 0xc2, 0x5c, 0x6f, 0xd4, 0x2f, 0x03, 0xda, 0xf8, 0x63, 0xb0, 0xc5, 0xe8, 0x43, 0xf7, 0x0c,
-	0xe7, 0xa3, 0xc1, 0x5a, 0x88, 0x69, 0x4c, 0x48, 0xd9, 0x2c, 0x31, 0xd2, 0x0a, 0xcd, 0x32, 0x46,
-	0x66, 0xa1, 0x59, 0x85, 0xd9, 0xf7, 0x08, 0xa6, 0xe5, 0xec, 0x31, 0x2d, 0xcd, 0xd9, 0x66, 0x5a,
-	0x16, 0x86, 0x14, 0xda, 0x04, 0x5b, 0x0c, 0x29, 0x93, 0xaa, 0x31, 0xb4, 0xcc, 0x1c, 0xb7, 0xf2,
-	0x97, 0x2c, 0x77, 0xe8, 0x47, 0x96, 0xc8, 0x52, 0x0c, 0x88, 0xa2, 0xe9, 0xa8, 0x3c, 0x0b, 0x17,
-	0xc5, 0xa3, 0xca, 0xaa, 0xf7, 0x6c, 0x56, 0xb6, 0x30, 0x59, 0xcc, 0xa8, 0xc5, 0xa7, 0x8f, 0x02,
-	0xb8, 0x73, 0xc5, 0xb3, 0x40, 0xef, 0x8e, 0x0c, 0xc6, 0x0f, 0x86, 0xc6, 0xca, 0x5b, 0xb4, 0x54,
-	0x8c, 0xf6, 0x57, 0xa7, 0x6f, 0x9a, 0x13, 0xa7, 0x67, 0x4d, 0xeb, 0xd5, 0x59, 0xd3, 0xfa, 0xfb,
-	0xac, 0x69, 0xfd, 0x72, 0xde, 0x9c, 0x78, 0x75, 0xde, 0x9c, 0xf8, 0xf3, 0xbc, 0x39, 0xf1, 0xe2,
-	0x83, 0x83, 0x88, 0x1f, 0xf6, 0x83, 0xf5, 0x90, 0x75, 0x5b, 0x21, 0x0b, 0x8f, 0x52, 0x86, 0xc3,
-	0x43, 0x12, 0xb4, 0x94, 0xeb, 0x30, 0x8e, 0x68, 0xc2, 0x5b, 0x79, 0x9c, 0xa0, 0x24, 0xff, 0x2a,
-	0x3c, 0xfc, 0x27, 0x00, 0x00, 0xff, 0xff, 0xc0, 0x8b, 0x1b, 0xb9, 0x7e, 0x0c, 0x00, 0x00,
+	// 1488 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x4b, 0x6f, 0xdb, 0xc6,
+	0x16, 0x36, 0x2d, 0x5a, 0x96, 0x8f, 0x1e, 0x76, 0x26, 0x7e, 0xc8, 0xcc, 0x8d, 0xec, 0x10, 0x09,
+	0xe2, 0x9b, 0x7b, 0xaf, 0x1d, 0x38, 0xb8, 0xf7, 0x36, 0x0d, 0x1a, 0xc4, 0xb6, 0xe4, 0x86, 0x89,
+	0x5f, 0x18, 0xc7, 0x2d, 0x90, 0x8d, 0x40, 0x91, 0x93, 0x98, 0x35, 0xc5, 0x51, 0xc9, 0x51, 0x52,
+	0x65, 0xd5, 0x5d, 0xb7, 0x5d, 0x74, 0xdf, 0x3f, 0xd1, 0xdf, 0x50, 0x78, 0xd7, 0xac, 0x8a, 0xa2,
+	0x8b, 0xa0, 0x71, 0x16, 0xfd, 0x1b, 0xc5, 0x3c, 0x28, 0x51, 0xaf, 0x38, 0x36, 0x1c, 0x74, 0x47,
+	0x9e, 0xef, 0x3b, 0x87, 0xe7, 0x9c, 0xf9, 0x66, 0xe6, 0x48, 0x30, 0x5f, 0xb3, 0x23, 0xdb, 0x67,
+	0x8d, 0xda, 0x8a, 0x43, 0x03, 0x16, 0x52, 0xdf, 0x27, 0xe1, 0x72, 0x23, 0xa4, 0x8c, 0xa2, 0x4c,
+	0x0c, 0x19, 0x33, 0x09, 0x52, 0xbd, 0x4e, 0x03, 0x49, 0x30, 0xa6, 0x9f, 0xd3, 0xe7, 0x54, 0x3c,
+	0xae, 0xf0, 0x27, 0x69, 0x35, 0x4f, 0x34, 0xc8, 0x6d, 0xd3, 0x66, 0xc0, 0x30, 0xf9, 0xba, 0x49,
+	0x22, 0x86, 0x16, 0x20, 0xeb, 0x05, 0x11, 0xb3, 0x03, 0x87, 0x54, 0x3d, 0xb7, 0xa8, 0x2d, 0x6a,
+	0x4b, 0x13, 0x18, 0x62, 0x93, 0xe5, 0x22, 0x04, 0xfa, 0x2b, 0x1a, 0x90, 0xe2, 0xa8, 0x40, 0xc4,
+	0x33, 0xfa, 0x17, 0x80, 0xe3, 0x37, 0x23, 0x46, 0x42, 0xee, 0x93, 0x5a, 0xd4, 0x96, 0x72, 0xeb,
+	0xb9, 0xe3, 0x37, 0x0b, 0x23, 0xbf, 0xbf, 0x59, 0xd0, 0x0f, 0x0e, 0xac, 0x32, 0x9e, 0x50, 0xb8,
+	0xe5, 0xa2, 0x9b, 0x90, 0x89, 0x18, 0x0d, 0x45, 0x78, 0x7d, 0x00, 0x75, 0x5c, 0xa0, 0x96, 0xcb,
+	0xa3, 0x86, 0x32, 0x2b, 0x4e, 0x1d, 0x1b, 0x14, 0x55, 0xe1, 0x96, 0x8b, 0x0c, 0xc8, 0x30, 0xfb,
+	0x88, 0xd0, 0x17, 0x24, 0x2c, 0xa6, 0x17, 0xb5, 0xa5, 0x0c, 0x6e, 0xbf, 0x9b, 0xc7, 0xa3, 0x90,
+	0x57, 0x45, 0x46, 0x0d, 0x1a, 0x44, 0x84, 0xe7, 0x50, 0xe7, 0x86, 0xb8, 0xc4, 0xbe, 0x1c, 0x04,
+	0x6a, 0xb9, 0x68, 0x05, 0x72, 0xae, 0x17, 0x12, 0x87, 0xd1, 0xb0, 0xc5, 0xc9, 0xa3, 0x03, 0xc8,
+	0xd9, 0x36, 0xc3, 0x72, 0x79, 0xff, 0x5e, 0x86, 0x1e, 0x23, 0x55, 0x46, 0x8f, 0x48, 0x20, 0x7b,
+	0x81, 0x41, 0x98, 0x9e, 0x70, 0x0b, 0xba, 0x0d, 0xd3, 0x3e, 0xb1, 0x23, 0x52, 0x75, 0x9b, 0xa1,
+	0xcd, 0x3c, 0x1a, 0x54, 0x03, 0x3b, 0xa0, 0x91, 0x68, 0x45, 0x0a, 0x23, 0x81, 0x95, 0x15, 0xb4,
+	0xc3, 0x11, 0x34, 0x0d, 0x63, 0xa4, 0x41, 0x9d, 0x43, 0xd1, 0x02, 0x1d, 0xcb, 0x17, 0x74, 0x0f,
+	0x0a, 0x8d, 0x90, 0xbc, 0xf0, 0x68, 0x33, 0xaa, 0x8a, 0x6c, 0x45, 0xd9, 0xd9, 0xd5, 0xe9, 0xe5,
+	0x78, 0xfd, 0x97, 0x45, 0xcd, 0x9b, 0x24, 0x70, 0x08, 0xce, 0xc7, 0x5c, 0x61, 0x43, 0xff, 0x87,
+	0x1c, 0x6d, 0x90, 0xa0, 0x4a, 0x6b, 0x5f, 0x11, 0x87, 0x45, 0xc5, 0xf1, 0xc5, 0x54, 0xb7, 0xeb,
+	0xae, 0x00, 0xb6, 0x09, 0xb3, 0x71, 0x96, 0x33, 0xe5, 0x7b, 0x64, 0x3e, 0x06, 0xe8, 0x44, 0xfd,
+	0xf0, 0x36, 0xb6, 0x4b, 0x18, 0x4d, 0x94, 0x60, 0xde, 0x85, 0xc2, 0x41, 0x50, 0x4f, 0xaa, 0xef,
+	0x43, 0x03, 0x9a, 0x97, 0x60, 0xb2, 0xed, 0x2a, 0xd7, 0xd4, 0x7c, 0x00, 0x33, 0x0f, 0x89, 0x1d,
+	0xb2, 0x1a, 0xb1, 0xd9, 0xf6, 0xb9, 0x82, 0x3e, 0x82, 0xd9, 0xde, 0x08, 0x4a, 0x2f, 0xc3, 0x16,
+	0x4d, 0x1b, 0xb6, 0x68, 0x7c, 0x63, 0xe5, 0x37, 0x42, 0x62, 0x33, 0x12, 0xa7, 0xd1, 0x2b, 0x25,
+	0xed, 0x34, 0x29, 0x21, 0xd0, 0x03, 0xbb, 0xde, 0xde, 0x69, 0xfc, 0x19, 0xdd, 0x81, 0x74, 0x83,
+	0xfa, 0x9e, 0xd3, 0x12, 0xca, 0xca, 0xae, 0x5e, 0xe9, 0x2c, 0x19, 0x26, 0x0d, 0xdf, 0x73, 0x44,
+	0x0a, 0x7b, 0x82, 0x82, 0x15, 0xb5, 0x67, 0x23, 0xe9, 0xef, 0xdf, 0x48, 0xb7, 0x60, 0xec, 0x19,
+	0x5f, 0x5c, 0xa1, 0xb6, 0x61, 0x72, 0x92, 0x14, 0xf3, 0x53, 0x28, 0xc4, 0x35, 0xaa, 0x46, 0x2d,
+	0x81, 0x5e, 0x27, 0xcc, 0x2e, 0x6a, 0xbd, 0xce, 0x09, 0x41, 0x09, 0x86, 0xf9, 0x9d, 0x06, 0x97,
+	0xf6, 0x99, 0xcd, 0xd6, 0x5b, 0x7b, 0x36, 0x3b, 0xbc, 0xd0, 0x26, 0xfd, 0x07, 0x90, 0x17, 0x38,
+	0x7e, 0xd3, 0x25, 0xd5, 0x90, 0x3c, 0x23, 0x21, 0xcf, 0x35, 0x12, 0x0d, 0xcb, 0xe0, 0x4b, 0x0a,
+	0xc1, 0x6d, 0xc0, 0xfc, 0x41, 0x83, 0x49, 0x99, 0x89, 0x55, 0x8e, 0xf3, 0xf8, 0x27, 0x4c, 0xc8,
+	0xbd, 0x31, 0x2c, 0x89, 0x8c, 0x84, 0x2d, 0x77, 0xc8, 0xd7, 0x46, 0x87, 0x7c, 0x0d, 0xdd, 0x84,
+	0xc9, 0x98, 0xfe, 0x8a, 0xd6, 0x6b, 0x5e, 0x3b, 0xb3, 0x82, 0x32, 0x3f, 0x95, 0x56, 0xf3, 0x27,
+	0x0d, 0x72, 0x3c, 0xad, 0xb3, 0xf7, 0x16, 0xdd, 0x04, 0x9d, 0xb5, 0x1a, 0xb2, 0x29, 0x85, 0xd5,
+	0xcb, 0x1d, 0x66, 0x25, 0x60, 0x61, 0xeb, 0x49, 0xab, 0x41, 0xb0, 0x20, 0xa0, 0xbb, 0x5c, 0x19,
+	0x89, 0x0e, 0xf1, 0x53, 0xe0, 0x72, 0x52, 0x52, 0x0a, 0x5b, 0xd7, 0x79, 0xf1, 0x38, 0x41, 0x46,
+	0xb3, 0x90, 0x96, 0xf9, 0x0b, 0x41, 0x65, 0xb0, 0x7a, 0x33, 0xbf, 0xd5, 0x20, 0x7f, 0x10, 0xf8,
+	0x5e, 0x70, 0x74, 0xa1, 0x6b, 0xda, 0x96, 0x65, 0xea, 0x74, 0x59, 0xd6, 0xa0, 0x10, 0x67, 0xa0,
+	0x5a, 0x77, 0x86, 0xe5, 0xbc, 0x01, 0x05, 0x45, 0x75, 0x89, 0x4f, 0x18, 0x71, 0xd5, 0x52, 0xe6,
+	0xa5, 0xb5, 0x2c, 0x8d, 0xe6, 0x37, 0x90, 0xdd, 0x27, 0xb6, 0x7f, 0x0e, 0xbd, 0x20, 0xd0, 0x23,
+	0xef, 0x95, 0xac, 0x2e, 0x85, 0xc5, 0xf3, 0x99, 0xaa, 0x2b, 0x40, 0x4e, 0x7e, 0x59, 0x9d, 0x7b,
+	0x2d, 0xc8, 0x6d, 0x1f, 0xb9, 0x5e, 0x98, 0x48, 0xa5, 0x61, 0x87, 0x64, 0xf8, 0x79, 0x97, 0x91,
+	0xf0, 0x05, 0x34, 0xfa, 0x01, 0xe4, 0xd5, 0xa7, 0x55, 0x9f, 0xcf, 0xba, 0xd4, 0x3c, 0x79, 0x5c,
+	0xff, 0x7b, 0x92, 0x9f, 0x84, 0x3c, 0xae, 0x27, 0x92, 0x37, 0xef, 0x43, 0x76, 0xcb, 0x8b, 0xd8,
+	0x79, 0x65, 0x6b, 0xfe, 0xaa, 0xf1, 0x00, 0x17, 0xac, 0xfb, 0x2e, 0x61, 0xa5, 0xde, 0x2b, 0xac,
+	0x8f, 0x76, 0xcc, 0x17, 0x20, 0xb7, 0x95, 0xd8, 0x4d, 0xe6, 0x2f, 0x1a, 0xe4, 0x31, 0xe1, 0xe9,
+	0x9d, 0xbb, 0xd4, 0x79, 0xc8, 0x50, 0xdf, 0xad, 0x26, 0xca, 0x1d, 0xa7, 0xbe, 0xbb, 0xc3, 0x2b,
+	0x9e, 0x87, 0x4c, 0x40, 0x5e, 0x4a, 0x28, 0x25, 0xa1, 0x80, 0xbc, 0x14, 0xd0, 0x47, 0xab, 0x70,
+	0x0a, 0x0a, 0x71, 0x41, 0xaa, 0xc6, 0xb7, 0x1a, 0x18, 0xed, 0x61, 0x60, 0xdd, 0xa7, 0xb5, 0x7d,
+	0x12, 0xbe, 0x20, 0x49, 0x9d, 0x46, 0xc2, 0x30, 0x54, 0xa7, 0x12, 0xb6, 0x5c, 0x74, 0x0d, 0x72,
+	0x6a, 0x5a, 0xaf, 0xda, 0xae, 0x1b, 0xaa, 0x72, 0xb3, 0xca, 0xb6, 0xe6, 0xba, 0x21, 0xba, 0x02,
+	0x13, 0xae, 0xcd, 0x6c, 0x89, 0xcb, 0x9a, 0x33, 0xdc, 0x20, 0xc0, 0x78, 0xe0, 0xd6, 0x13, 0x03,
+	0xf7, 0x0d, 0x28, 0x38, 0x76, 0xc3, 0x76, 0x3c, 0xd6, 0xaa, 0xd6, 0x5a, 0x8c, 0x44, 0xa2, 0xc8,
+	0x14, 0xce, 0xc7, 0xd6, 0x75, 0x6e, 0x44, 0x57, 0x01, 0x9a, 0x11, 0x71, 0x15, 0x25, 0x2d, 0x28,
+	0x13, 0xdc, 0x22, 0x60, 0xf3, 0x7f, 0x70, 0x65, 0x60, 0x89, 0x6a, 0x33, 0xcf, 0xc1, 0xb8, 0xeb,
+	0x45, 0x47, 0x71, 0x85, 0x63, 0x38, 0xcd, 0x5f, 0x2d, 0xd7, 0xfc, 0x51, 0x83, 0x69, 0x4c, 0x1a,
+	0x34, 0x64, 0x9b, 0xb6, 0xe7, 0x37, 0x43, 0x72, 0xbe, 0x53, 0x30, 0xd1, 0x0d, 0xf1, 0x8c, 0x6e,
+	0x83, 0x7e, 0xe4, 0x05, 0x52, 0xe6, 0x85, 0xd5, 0x7f, 0xf4, 0x8d, 0x36, 0xea, 0x6b, 0x8f, 0xbd,
+	0xc0, 0xc5, 0x82, 0xc9, 0x2f, 0x21, 0x97, 0x30, 0xdb, 0xf3, 0x55, 0x77, 0xd4, 0x9b, 0x39, 0x07,
+	0x33, 0x3d, 0x09, 0xca, 0x9a, 0x6e, 0xfd, 0xac, 0x01, 0xea, 0x8f, 0x86, 0xae, 0xc3, 0x22, 0xae,
+	0xec, 0x6d, 0x59, 0x1b, 0x6b, 0xd5, 0xcd, 0x35, 0x6b, 0xeb, 0x00, 0x57, 0xaa, 0x8f, 0xad, 0x9d,
+	0x72, 0xf5, 0x60, 0x67, 0x7f, 0xaf, 0xb2, 0x61, 0x6d, 0x5a, 0x95, 0xf2, 0xd4, 0xc8, 0x7b, 0x58,
+	0xb8, 0xb2, 0xb6, 0xf1, 0x70, 0x6d, 0x7d, 0xab, 0x32, 0xa5, 0xa1, 0x6b, 0x70, 0x75, 0x20, 0xcb,
+	0xda, 0xad, 0x56, 0x30, 0xde, 0xc5, 0x53, 0xa3, 0xc8, 0x84, 0xd2, 0x40, 0x4a, 0xd9, 0xfa, 0xa2,
+	0x82, 0x3f, 0xaf, 0xec, 0x3c, 0x99, 0x4a, 0xa1, 0xab, 0x30, 0x3f, 0x90, 0xb3, 0xbf, 0xb5, 0xfb,
+	0xe5, 0x94, 0xbe, 0xfa, 0xe7, 0x38, 0xc0, 0x46, 0xfb, 0x47, 0x20, 0xfa, 0x04, 0xc6, 0xe4, 0x64,
+	0x3f, 0xdb, 0x23, 0x73, 0xb5, 0x34, 0xc6, 0x5c, 0x9f, 0x5d, 0xad, 0xf2, 0x7d, 0x18, 0x57, 0x93,
+	0x34, 0x2a, 0x76, 0x38, 0xdd, 0x73, 0xb9, 0x31, 0x3f, 0x00, 0x51, 0xfe, 0xfb, 0x50, 0xe8, 0x1e,
+	0x9a, 0xd1, 0x42, 0x87, 0x3c, 0x70, 0x20, 0x37, 0x16, 0x87, 0x13, 0x54, 0xd0, 0x7b, 0x90, 0x96,
+	0x83, 0x25, 0x4a, 0xe4, 0xdd, 0x35, 0x4e, 0x1b, 0xc5, 0x7e, 0x40, 0x39, 0xaf, 0x01, 0x74, 0x06,
+	0x4b, 0x94, 0x98, 0x90, 0xfb, 0xc6, 0x4d, 0x63, 0xb6, 0x1b, 0x6c, 0x87, 0xf8, 0x0c, 0x32, 0xf1,
+	0x44, 0x88, 0xe6, 0x7b, 0x03, 0x58, 0xe5, 0xd3, 0xdc, 0xef, 0x41, 0x5a, 0x0e, 0x20, 0xc9, 0xf4,
+	0xbb, 0x86, 0x22, 0xa3, 0xd8, 0x0f, 0x28, 0xe7, 0xff, 0x82, 0xce, 0xef, 0x77, 0x34, 0x93, 0x08,
+	0xde, 0x99, 0x34, 0x8c, 0xd9, 0x5e, 0xb3, 0x72, 0xe3, 0x0a, 0xe0, 0x77, 0x71, 0x97, 0x02, 0x12,
+	0x73, 0x81, 0x31, 0xd7, 0x67, 0xef, 0x78, 0xe2, 0x7a, 0x8f, 0x27, 0xae, 0x0f, 0xf6, 0xec, 0xba,
+	0x31, 0xd1, 0x5d, 0xd0, 0xf9, 0x8d, 0x99, 0x4c, 0x35, 0x71, 0x83, 0x26, 0x6b, 0x2c, 0xc7, 0x67,
+	0xbf, 0x98, 0x40, 0x6f, 0x6b, 0xbc, 0x4a, 0x7e, 0xa7, 0x74, 0xbb, 0x76, 0xda, 0x33, 0xdb, 0x6b,
+	0xee, 0x74, 0x56, 0x1e, 0xd4, 0xc9, 0xce, 0x76, 0xdd, 0x45, 0x46, 0xb1, 0x1f, 0x50, 0xce, 0x35,
+	0xb8, 0x3c, 0xe0, 0xbc, 0x43, 0xd7, 0x07, 0xc8, 0xb1, 0xef, 0xc4, 0x37, 0x6e, 0x9c, 0xc2, 0x52,
+	0xdf, 0xd8, 0x83, 0x7c, 0xd7, 0xc9, 0x83, 0x4a, 0x5d, 0xc7, 0x58, 0xdf, 0x99, 0x69, 0x2c, 0x0c,
+	0xc5, 0x65, 0xc4, 0xf5, 0x47, 0xc7, 0x6f, 0x4b, 0x23, 0xc7, 0x27, 0x25, 0xed, 0xf5, 0x49, 0x49,
+	0xfb, 0xe3, 0xa4, 0xa4, 0x7d, 0xff, 0xae, 0x34, 0xf2, 0xfa, 0x5d, 0x69, 0xe4, 0xb7, 0x77, 0xa5,
+	0x91, 0xa7, 0xff, 0x7e, 0xee, 0xb1, 0xc3, 0x66, 0x6d, 0xd9, 0xa1, 0xf5, 0x15, 0x87, 0x3a, 0x47,
+	0x21, 0xb5, 0x9d, 0x43, 0xb7, 0xb6, 0x22, 0x83, 0x3a, 0xbe, 0x47, 0x02, 0xb6, 0x12, 0x7f, 0xa1,
+	0x96, 0x16, 0xff, 0xfa, 0xdc, 0xf9, 0x6b, 0x00, 0xe8, 0x52, 0x78, 0xf4, 0x49, 0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1100,10 +1416,17 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type ControllerClient interface {
 	// Mount registers a Pebble instance and acquires exclusive write access
-	// to its store directory. Returns the mount ID and directory ID.
+	// to its store directory. Returns the mount ID and directory ID. Fails
+	// with MOUNT_CONFLICT if the store is mounted by another instance, unless
+	// the request takes over its mount.
 	Mount(ctx context.Context, in *MountRequest, opts ...grpc.CallOption) (*MountResponse, error)
 	// Unmount releases the write lock on a store directory.
 	Unmount(ctx context.Context, in *UnmountRequest, opts ...grpc.CallOption) (*UnmountResponse, error)
+	// HeartbeatMount renews the lease of a mount. A mount whose lease is not
+	// renewed within its lease duration expires, releasing the write lock on
+	// the store directory. Fails with NOT_MOUNTED if the mount has expired,
+	// been unmounted, or been taken over.
+	HeartbeatMount(ctx context.Context, in *HeartbeatMountRequest, opts ...grpc.CallOption) (*HeartbeatMountResponse, error)
 	// Create allocates a new file in a directory and selects replicas.
 	// Requires the caller to hold a mount for the directory, or the directory
 	// must not be mounted by anyone.
@@ -1141,6 +1464,11 @@ type ControllerClient interface {
 	// capacity updates. Blob servers should call this periodically to maintain
 	// liveness.
 	HeartbeatBlobServer(ctx context.Context, in *HeartbeatBlobServerRequest, opts ...grpc.CallOption) (*HeartbeatBlobServerResponse, error)
+	// ReportFailure reports that a client observed a replica of an object
+	// persistently failing, so that the controller can check the blob server
+	// and schedule repair of the replica.
+	// Does not require a mount.
+	ReportFailure(ctx context.Context, in *ReportFailureRequest, opts ...grpc.CallOption) (*ReportFailureResponse, error)
 }
 
 type controllerClient struct {
@@ -1169,6 +1497,15 @@ func (c *controllerClient) Unmount(ctx context.Context, in *UnmountRequest, opts
 	return out, nil
 }
 
+func (c *controllerClient) HeartbeatMount(ctx context.Context, in *HeartbeatMountRequest, opts ...grpc.CallOption) (*HeartbeatMountResponse, error) {
+	out := new(HeartbeatMountResponse)
+	err := c.cc.Invoke(ctx, "/basaltpb.Controller/HeartbeatMount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controllerClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
 	out := new(CreateResponse)
 	err := c.cc.Invoke(ctx, "/basaltpb.Controller/Create", in, out, opts...)
@@ -1291,13 +1628,29 @@ func (c *controllerClient) HeartbeatBlobServer(ctx context.Context, in *Heartbea
 	return out, nil
 }
 
+func (c *controllerClient) ReportFailure(ctx context.Context, in *ReportFailureRequest, opts ...grpc.CallOption) (*ReportFailureResponse, error) {
+	out := new(ReportFailureResponse)
+	err := c.cc.Invoke(ctx, "/basaltpb.Controller/ReportFailure", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControllerServer is the server API for Controller service.
 type ControllerServer interface {
 	// Mount registers a Pebble instance and acquires exclusive write access
-	// to its store directory. Returns the mount ID and directory ID.
+	// to its store directory. Returns the mount ID and directory ID. Fails
+	// with MOUNT_CONFLICT if the store is mounted by another instance, unless
+	// the request takes over its mount.
 	Mount(context.Context, *MountRequest) (*MountResponse, error)
 	// Unmount releases the write lock on a store directory.
 	Unmount(context.Context, *UnmountRequest) (*UnmountResponse, error)
+	// HeartbeatMount renews the lease of a mount. A mount whose lease is not
+	// renewed within its lease duration expires, releasing the write lock on
+	// the store directory. Fails with NOT_MOUNTED if the mount has expired,
+	// been unmounted, or been taken over.
+	HeartbeatMount(context.Context, *HeartbeatMountRequest) (*HeartbeatMountResponse, error)
 	// Create allocates a new file in a directory and selects replicas.
 	// Requires the caller to hold a mount for the directory, or the directory
 	// must not be mounted by anyone.
@@ -1335,6 +1688,11 @@ type ControllerServer interface {
 	// capacity updates. Blob servers should call this periodically to maintain
 	// liveness.
 	HeartbeatBlobServer(context.Context, *HeartbeatBlobServerRequest) (*HeartbeatBlobServerResponse, error)
+	// ReportFailure reports that a client observed a replica of an object
+	// persistently failing, so that the controller can check the blob server
+	// and schedule repair of the replica.
+	// Does not require a mount.
+	ReportFailure(context.Context, *ReportFailureRequest) (*ReportFailureResponse, error)
 }
 
 // UnimplementedControllerServer can be embedded to have forward compatible implementations.
@@ -1347,6 +1705,9 @@ func (*UnimplementedControllerServer) Mount(ctx context.Context, req *MountReque
 func (*UnimplementedControllerServer) Unmount(ctx context.Context, req *UnmountRequest) (*UnmountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Unmount not implemented")
 }
+func (*UnimplementedControllerServer) HeartbeatMount(ctx context.Context, req *HeartbeatMountRequest) (*HeartbeatMountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HeartbeatMount not implemented")
+}
 func (*UnimplementedControllerServer) Create(ctx context.Context, req *CreateRequest) (*CreateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
@@ -1380,6 +1741,9 @@ func (*UnimplementedControllerServer) Rename(ctx context.Context, req *RenameReq
 func (*UnimplementedControllerServer) HeartbeatBlobServer(ctx context.Context, req *HeartbeatBlobServerRequest) (*HeartbeatBlobServerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HeartbeatBlobServer not implemented")
 }
+func (*UnimplementedControllerServer) ReportFailure(ctx context.Context, req *ReportFailureRequest) (*ReportFailureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportFailure not implemented")
+}
 
 func RegisterControllerServer(s *grpc.Server, srv ControllerServer) {
 	s.RegisterService(&_Controller_serviceDesc, srv)
@@ -1421,6 +1785,24 @@ func _Controller_Unmount_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Controller_HeartbeatMount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatMountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).HeartbeatMount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/basaltpb.Controller/HeartbeatMount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).HeartbeatMount(ctx, req.(*HeartbeatMountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Controller_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateRequest)
 	if err := dec(in); err != nil {
@@ -1622,6 +2004,24 @@ func _Controller_HeartbeatBlobServer_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Controller_ReportFailure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportFailureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ReportFailure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/basaltpb.Controller/ReportFailure",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ReportFailure(ctx, req.(*ReportFailureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Controller_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "basaltpb.Controller",
 	HandlerType: (*ControllerServer)(nil),
@@ -1634,6 +2034,10 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Unmount",
 			Handler:    _Controller_Unmount_Handler,
 		},
+		{
+			MethodName: "HeartbeatMount",
+			Handler:    _Controller_HeartbeatMount_Handler,
+		},
 		{
 			MethodName: "Create",
 			Handler:    _Controller_Create_Handler,
@@ -1674,6 +2078,10 @@ var _Controller_serviceDesc = grpc.ServiceDesc{
 			MethodName: "HeartbeatBlobServer",
 			Handler:    _Controller_HeartbeatBlobServer_Handler,
 		},
+		{
+			MethodName: "ReportFailure",
+			Handler:    _Controller_ReportFailure_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1705,6 +2113,26 @@ func (m *MountRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Takeover {
+		i--
+		if m.Takeover {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	{
+		size := m.RequestId.Size()
+		i -= size
+		if _, err := m.RequestId.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintController(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x2a
 	{
 		size := m.StoreId.Size()
 		i -= size
@@ -1762,6 +2190,42 @@ func (m *MountResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.OpenObjects) > 0 {
+		for iNdEx := len(m.OpenObjects) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.OpenObjects[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintController(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.PreviousMount != nil {
+		{
+			size, err := m.PreviousMount.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Epoch != 0 {
+		i = encodeVarintController(dAtA, i, uint64(m.Epoch))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.LeaseDurationNanos != 0 {
+		i = encodeVarintController(dAtA, i, uint64(m.LeaseDurationNanos))
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.WriteToken) > 0 {
 		i -= len(m.WriteToken)
 		copy(dAtA[i:], m.WriteToken)
@@ -1792,7 +2256,7 @@ func (m *MountResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *UnmountRequest) Marshal() (dAtA []byte, err error) {
+func (m *MountFence) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1802,16 +2266,21 @@ func (m *UnmountRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UnmountRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *MountFence) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UnmountRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *MountFence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if m.Epoch != 0 {
+		i = encodeVarintController(dAtA, i, uint64(m.Epoch))
+		i--
+		dAtA[i] = 0x10
+	}
 	{
 		size := m.MountId.Size()
 		i -= size
@@ -1825,7 +2294,40 @@ func (m *UnmountRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *UnmountResponse) Marshal() (dAtA []byte, err error) {
+func (m *UnmountRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UnmountRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UnmountRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size := m.MountId.Size()
+		i -= size
+		if _, err := m.MountId.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintController(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *UnmountResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1848,6 +2350,67 @@ func (m *UnmountResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *HeartbeatMountRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeartbeatMountRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HeartbeatMountRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size := m.MountId.Size()
+		i -= size
+		if _, err := m.MountId.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintController(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *HeartbeatMountResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeartbeatMountResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HeartbeatMountResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.LeaseDurationNanos != 0 {
+		i = encodeVarintController(dAtA, i, uint64(m.LeaseDurationNanos))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *CreateRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1868,6 +2431,28 @@ func (m *CreateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Fence != nil {
+		{
+			size, err := m.Fence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	{
+		size := m.RequestId.Size()
+		i -= size
+		if _, err := m.RequestId.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintController(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
 	if m.Policy != nil {
 		{
 			size, err := m.Policy.MarshalToSizedBuffer(dAtA[:i])
@@ -2122,6 +2707,18 @@ func (m *UnlinkRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Fence != nil {
+		{
+			size, err := m.Fence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Name) > 0 {
 		i -= len(m.Name)
 		copy(dAtA[i:], m.Name)
@@ -2205,6 +2802,18 @@ func (m *SealRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Fence != nil {
+		{
+			size, err := m.Fence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.Size_ != 0 {
 		i = encodeVarintController(dAtA, i, uint64(m.Size_))
 		i--
@@ -2266,6 +2875,18 @@ func (m *MkdirRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Fence != nil {
+		{
+			size, err := m.Fence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Name) > 0 {
 		i -= len(m.Name)
 		copy(dAtA[i:], m.Name)
@@ -2339,6 +2960,18 @@ func (m *RmdirRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Fence != nil {
+		{
+			size, err := m.Fence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Name) > 0 {
 		i -= len(m.Name)
 		copy(dAtA[i:], m.Name)
@@ -2435,6 +3068,28 @@ func (m *LinkRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Fence != nil {
+		{
+			size, err := m.Fence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	{
+		size := m.RequestId.Size()
+		i -= size
+		if _, err := m.RequestId.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintController(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
 	{
 		size := m.ObjectId.Size()
 		i -= size
@@ -2508,6 +3163,28 @@ func (m *RenameRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Fence != nil {
+		{
+			size, err := m.Fence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintController(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	{
+		size := m.RequestId.Size()
+		i -= size
+		if _, err := m.RequestId.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintController(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
 	if len(m.NewName) > 0 {
 		i -= len(m.NewName)
 		copy(dAtA[i:], m.NewName)
@@ -2650,6 +3327,81 @@ func (m *HeartbeatBlobServerResponse) MarshalToSizedBuffer(dAtA []byte) (int, er
 	return len(dAtA) - i, nil
 }
 
+func (m *ReportFailureRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportFailureRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReportFailureRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Detail) > 0 {
+		i -= len(m.Detail)
+		copy(dAtA[i:], m.Detail)
+		i = encodeVarintController(dAtA, i, uint64(len(m.Detail)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Kind != 0 {
+		i = encodeVarintController(dAtA, i, uint64(m.Kind))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Addr) > 0 {
+		i -= len(m.Addr)
+		copy(dAtA[i:], m.Addr)
+		i = encodeVarintController(dAtA, i, uint64(len(m.Addr)))
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size := m.ObjectId.Size()
+		i -= size
+		if _, err := m.ObjectId.MarshalTo(dAtA[i:]); err != nil {
+			return 0, err
+		}
+		i = encodeVarintController(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *ReportFailureResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportFailureResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReportFailureResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintController(dAtA []byte, offset int, v uint64) int {
 	offset -= sovController(v)
 	base := offset
@@ -2679,6 +3431,11 @@ func (m *MountRequest) Size() (n int) {
 	n += 1 + l + sovController(uint64(l))
 	l = m.StoreId.Size()
 	n += 1 + l + sovController(uint64(l))
+	l = m.RequestId.Size()
+	n += 1 + l + sovController(uint64(l))
+	if m.Takeover {
+		n += 2
+	}
 	return n
 }
 
@@ -2696,10 +3453,26 @@ func (m *MountResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovController(uint64(l))
 	}
+	if m.LeaseDurationNanos != 0 {
+		n += 1 + sovController(uint64(m.LeaseDurationNanos))
+	}
+	if m.Epoch != 0 {
+		n += 1 + sovController(uint64(m.Epoch))
+	}
+	if m.PreviousMount != nil {
+		l = m.PreviousMount.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
+	if len(m.OpenObjects) > 0 {
+		for _, e := range m.OpenObjects {
+			l = e.Size()
+			n += 1 + l + sovController(uint64(l))
+		}
+	}
 	return n
 }
 
-func (m *UnmountRequest) Size() (n int) {
+func (m *MountFence) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -2707,19 +3480,56 @@ func (m *UnmountRequest) Size() (n int) {
 	_ = l
 	l = m.MountId.Size()
 	n += 1 + l + sovController(uint64(l))
+	if m.Epoch != 0 {
+		n += 1 + sovController(uint64(m.Epoch))
+	}
 	return n
 }
 
-func (m *UnmountResponse) Size() (n int) {
+func (m *UnmountRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
+	l = m.MountId.Size()
+	n += 1 + l + sovController(uint64(l))
 	return n
 }
 
-func (m *CreateRequest) Size() (n int) {
+func (m *UnmountResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *HeartbeatMountRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.MountId.Size()
+	n += 1 + l + sovController(uint64(l))
+	return n
+}
+
+func (m *HeartbeatMountResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LeaseDurationNanos != 0 {
+		n += 1 + sovController(uint64(m.LeaseDurationNanos))
+	}
+	return n
+}
+
+func (m *CreateRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -2735,6 +3545,12 @@ func (m *CreateRequest) Size() (n int) {
 		l = m.Policy.Size()
 		n += 1 + l + sovController(uint64(l))
 	}
+	l = m.RequestId.Size()
+	n += 1 + l + sovController(uint64(l))
+	if m.Fence != nil {
+		l = m.Fence.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
 	return n
 }
 
@@ -2823,6 +3639,10 @@ func (m *UnlinkRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovController(uint64(l))
 	}
+	if m.Fence != nil {
+		l = m.Fence.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
 	return n
 }
 
@@ -2851,6 +3671,10 @@ func (m *SealRequest) Size() (n int) {
 	if m.Size_ != 0 {
 		n += 1 + sovController(uint64(m.Size_))
 	}
+	if m.Fence != nil {
+		l = m.Fence.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
 	return n
 }
 
@@ -2875,6 +3699,10 @@ func (m *MkdirRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovController(uint64(l))
 	}
+	if m.Fence != nil {
+		l = m.Fence.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
 	return n
 }
 
@@ -2901,6 +3729,10 @@ func (m *RmdirRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovController(uint64(l))
 	}
+	if m.Fence != nil {
+		l = m.Fence.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
 	return n
 }
 
@@ -2938,6 +3770,12 @@ func (m *LinkRequest) Size() (n int) {
 	}
 	l = m.ObjectId.Size()
 	n += 1 + l + sovController(uint64(l))
+	l = m.RequestId.Size()
+	n += 1 + l + sovController(uint64(l))
+	if m.Fence != nil {
+		l = m.Fence.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
 	return n
 }
 
@@ -2966,6 +3804,12 @@ func (m *RenameRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovController(uint64(l))
 	}
+	l = m.RequestId.Size()
+	n += 1 + l + sovController(uint64(l))
+	if m.Fence != nil {
+		l = m.Fence.Size()
+		n += 1 + l + sovController(uint64(l))
+	}
 	return n
 }
 
@@ -3019,8 +3863,39 @@ func (m *HeartbeatBlobServerResponse) Size() (n int) {
 	return n
 }
 
+func (m *ReportFailureRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectId.Size()
+	n += 1 + l + sovController(uint64(l))
+	l = len(m.Addr)
+	if l > 0 {
+		n += 1 + l + sovController(uint64(l))
+	}
+	if m.Kind != 0 {
+		n += 1 + sovController(uint64(m.Kind))
+	}
+	l = len(m.Detail)
+	if l > 0 {
+		n += 1 + l + sovController(uint64(l))
+	}
+	return n
+}
+
+func (m *ReportFailureResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
 func sovController(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+	return int((uint32(math_bits.Len64(x|1)+6) * 37) >> 8)
 }
 func sozController(x uint64) (n int) {
 	return sovController(uint64((x << 1) ^ uint64((int64(x) >> 63))))
@@ -3184,6 +4059,59 @@ func (m *MountRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.RequestId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Takeover", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Takeover = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -3334,6 +4262,114 @@ func (m *MountResponse) Unmarshal(dAtA []byte) error {
 				m.WriteToken = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseDurationNanos", wireType)
+			}
+			m.LeaseDurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LeaseDurationNanos |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Epoch", wireType)
+			}
+			m.Epoch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Epoch |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreviousMount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PreviousMount == nil {
+				m.PreviousMount = &MountFence{}
+			}
+			if err := m.PreviousMount.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OpenObjects", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OpenObjects = append(m.OpenObjects, &ObjectMeta{})
+			if err := m.OpenObjects[len(m.OpenObjects)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -3355,7 +4391,7 @@ func (m *MountResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UnmountRequest) Unmarshal(dAtA []byte) error {
+func (m *MountFence) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3378,10 +4414,10 @@ func (m *UnmountRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UnmountRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: MountFence: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UnmountRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MountFence: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3417,6 +4453,25 @@ func (m *UnmountRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Epoch", wireType)
+			}
+			m.Epoch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Epoch |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -3438,7 +4493,7 @@ func (m *UnmountRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UnmountResponse) Unmarshal(dAtA []byte) error {
+func (m *UnmountRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3461,34 +4516,575 @@ func (m *UnmountResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UnmountResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: UnmountRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UnmountResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UnmountRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		default:
-			iNdEx = preIndex
-			skippy, err := skipController(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthController
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MountId", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.MountId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UnmountResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowController
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UnmountResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UnmountResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeartbeatMountRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowController
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeartbeatMountRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeartbeatMountRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MountId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.MountId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeartbeatMountResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowController
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeartbeatMountResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeartbeatMountResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseDurationNanos", wireType)
+			}
+			m.LeaseDurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LeaseDurationNanos |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowController
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DirectoryId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.DirectoryId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Policy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Policy == nil {
+				m.Policy = &ReplicationPolicy{}
+			}
+			if err := m.Policy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.RequestId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fence", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fence == nil {
+				m.Fence = &MountFence{}
+			}
+			if err := m.Fence.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowController
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Meta == nil {
+				m.Meta = &ObjectMeta{}
+			}
+			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
 }
-func (m *CreateRequest) Unmarshal(dAtA []byte) error {
+func (m *StatByPathRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3511,10 +5107,10 @@ func (m *CreateRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CreateRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: StatByPathRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CreateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StatByPathRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3583,10 +5179,80 @@ func (m *CreateRequest) Unmarshal(dAtA []byte) error {
 			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeReferences", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IncludeReferences = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StatByIDRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowController
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StatByIDRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StatByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Policy", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectId", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -3596,28 +5262,65 @@ func (m *CreateRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthController
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthController
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Policy == nil {
-				m.Policy = &ReplicationPolicy{}
-			}
-			if err := m.Policy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ObjectId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeReferences", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IncludeReferences = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeZombies", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IncludeZombies = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -3639,7 +5342,7 @@ func (m *CreateRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CreateResponse) Unmarshal(dAtA []byte) error {
+func (m *StatResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3662,10 +5365,10 @@ func (m *CreateResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CreateResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: StatResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CreateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StatResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3704,6 +5407,79 @@ func (m *CreateResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= EntryType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field References", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.References = append(m.References, Reference{})
+			if err := m.References[len(m.References)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Zombie", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Zombie = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -3725,7 +5501,7 @@ func (m *CreateResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StatByPathRequest) Unmarshal(dAtA []byte) error {
+func (m *UnlinkRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3748,10 +5524,10 @@ func (m *StatByPathRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StatByPathRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: UnlinkRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StatByPathRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UnlinkRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3820,10 +5596,10 @@ func (m *StatByPathRequest) Unmarshal(dAtA []byte) error {
 			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IncludeReferences", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fence", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -3833,12 +5609,28 @@ func (m *StatByPathRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.IncludeReferences = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fence == nil {
+				m.Fence = &MountFence{}
+			}
+			if err := m.Fence.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -3860,7 +5652,7 @@ func (m *StatByPathRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StatByIDRequest) Unmarshal(dAtA []byte) error {
+func (m *UnlinkResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3883,10 +5675,10 @@ func (m *StatByIDRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StatByIDRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: UnlinkResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StatByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UnlinkResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3924,27 +5716,7 @@ func (m *StatByIDRequest) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IncludeReferences", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowController
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.IncludeReferences = bool(v != 0)
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IncludeZombies", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectDeleted", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -3961,7 +5733,7 @@ func (m *StatByIDRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.IncludeZombies = bool(v != 0)
+			m.ObjectDeleted = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -3983,7 +5755,7 @@ func (m *StatByIDRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StatResponse) Unmarshal(dAtA []byte) error {
+func (m *SealRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4006,17 +5778,17 @@ func (m *StatResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StatResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SealRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StatResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SealRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectId", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -4026,33 +5798,30 @@ func (m *StatResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthController
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthController
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Meta == nil {
-				m.Meta = &ObjectMeta{}
-			}
-			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ObjectId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
 			}
-			m.Type = 0
+			m.Size_ = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -4062,14 +5831,14 @@ func (m *StatResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= EntryType(b&0x7F) << shift
+				m.Size_ |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field References", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Fence", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4096,31 +5865,63 @@ func (m *StatResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.References = append(m.References, Reference{})
-			if err := m.References[len(m.References)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Fence == nil {
+				m.Fence = &MountFence{}
+			}
+			if err := m.Fence.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Zombie", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipController(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowController
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthController
 			}
-			m.Zombie = bool(v != 0)
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SealResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowController
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SealResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SealResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -4142,7 +5943,7 @@ func (m *StatResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UnlinkRequest) Unmarshal(dAtA []byte) error {
+func (m *MkdirRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4165,15 +5966,15 @@ func (m *UnlinkRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UnlinkRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: MkdirRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UnlinkRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MkdirRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DirectoryId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -4200,7 +6001,7 @@ func (m *UnlinkRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.DirectoryId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ParentId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -4236,6 +6037,42 @@ func (m *UnlinkRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fence", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fence == nil {
+				m.Fence = &MountFence{}
+			}
+			if err := m.Fence.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -4257,7 +6094,7 @@ func (m *UnlinkRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UnlinkResponse) Unmarshal(dAtA []byte) error {
+func (m *MkdirResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4280,15 +6117,15 @@ func (m *UnlinkResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UnlinkResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: MkdirResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UnlinkResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MkdirResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DirectoryId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -4315,30 +6152,10 @@ func (m *UnlinkResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ObjectId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.DirectoryId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectDeleted", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowController
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.ObjectDeleted = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -4360,7 +6177,7 @@ func (m *UnlinkResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SealRequest) Unmarshal(dAtA []byte) error {
+func (m *RmdirRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4383,15 +6200,15 @@ func (m *SealRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SealRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: RmdirRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SealRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RmdirRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -4418,15 +6235,15 @@ func (m *SealRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ObjectId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ParentId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			m.Size_ = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -4436,11 +6253,60 @@ func (m *SealRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Size_ |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fence", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fence == nil {
+				m.Fence = &MountFence{}
+			}
+			if err := m.Fence.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -4462,7 +6328,7 @@ func (m *SealRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SealResponse) Unmarshal(dAtA []byte) error {
+func (m *RmdirResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4485,10 +6351,10 @@ func (m *SealResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SealResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: RmdirResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SealResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RmdirResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		default:
@@ -4512,7 +6378,7 @@ func (m *SealResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *MkdirRequest) Unmarshal(dAtA []byte) error {
+func (m *ListRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4535,15 +6401,15 @@ func (m *MkdirRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: MkdirRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ListRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MkdirRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ListRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ParentId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DirectoryId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -4570,42 +6436,10 @@ func (m *MkdirRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ParentId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.DirectoryId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowController
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthController
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthController
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Name = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -4627,7 +6461,7 @@ func (m *MkdirRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *MkdirResponse) Unmarshal(dAtA []byte) error {
+func (m *LinkRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4650,10 +6484,10 @@ func (m *MkdirResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: MkdirResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: LinkRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MkdirResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LinkRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -4689,59 +6523,74 @@ func (m *MkdirResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipController(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthController
 			}
-			if (iNdEx + skippy) > l {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *RmdirRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowController
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectId", wireType)
 			}
-			if iNdEx >= l {
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if err := m.ObjectId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: RmdirRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RmdirRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ParentId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -4768,15 +6617,15 @@ func (m *RmdirRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ParentId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.RequestId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Fence", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -4786,23 +6635,27 @@ func (m *RmdirRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthController
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthController
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			if m.Fence == nil {
+				m.Fence = &MountFence{}
+			}
+			if err := m.Fence.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4825,7 +6678,7 @@ func (m *RmdirRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RmdirResponse) Unmarshal(dAtA []byte) error {
+func (m *LinkResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4848,10 +6701,10 @@ func (m *RmdirResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RmdirResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: LinkResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RmdirResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LinkResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		default:
@@ -4875,7 +6728,7 @@ func (m *RmdirResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ListRequest) Unmarshal(dAtA []byte) error {
+func (m *RenameRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4898,10 +6751,10 @@ func (m *ListRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ListRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: RenameRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ListRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RenameRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -4937,61 +6790,43 @@ func (m *ListRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipController(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthController
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldName", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *LinkRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowController
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthController
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: LinkRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LinkRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OldName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DirectoryId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NewName", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -5001,30 +6836,29 @@ func (m *LinkRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthController
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthController
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.DirectoryId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.NewName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -5034,29 +6868,30 @@ func (m *LinkRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthController
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthController
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			if err := m.RequestId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Fence", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -5066,22 +6901,25 @@ func (m *LinkRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthController
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthController
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ObjectId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Fence == nil {
+				m.Fence = &MountFence{}
+			}
+			if err := m.Fence.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5106,7 +6944,7 @@ func (m *LinkRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LinkResponse) Unmarshal(dAtA []byte) error {
+func (m *RenameResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5129,10 +6967,10 @@ func (m *LinkResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LinkResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: RenameResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LinkResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RenameResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		default:
@@ -5156,7 +6994,7 @@ func (m *LinkResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RenameRequest) Unmarshal(dAtA []byte) error {
+func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5179,15 +7017,15 @@ func (m *RenameRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RenameRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: HeartbeatBlobServerRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RenameRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: HeartbeatBlobServerRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DirectoryId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ServerId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -5214,13 +7052,13 @@ func (m *RenameRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.DirectoryId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ServerId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OldName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ControlAddr", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5248,11 +7086,11 @@ func (m *RenameRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OldName = string(dAtA[iNdEx:postIndex])
+			m.ControlAddr = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NewName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DataAddr", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5280,8 +7118,78 @@ func (m *RenameRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.NewName = string(dAtA[iNdEx:postIndex])
+			m.DataAddr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Zone", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthController
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthController
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Zone = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CapacityBytes", wireType)
+			}
+			m.CapacityBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CapacityBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UsedBytes", wireType)
+			}
+			m.UsedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UsedBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -5303,7 +7211,7 @@ func (m *RenameRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RenameResponse) Unmarshal(dAtA []byte) error {
+func (m *HeartbeatBlobServerResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5326,12 +7234,31 @@ func (m *RenameResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RenameResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: HeartbeatBlobServerResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RenameResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: HeartbeatBlobServerResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DiskId", wireType)
+			}
+			m.DiskId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowController
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DiskId |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -5353,7 +7280,7 @@ func (m *RenameResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
+func (m *ReportFailureRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5376,15 +7303,15 @@ func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HeartbeatBlobServerRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReportFailureRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HeartbeatBlobServerRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReportFailureRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ServerId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -5411,13 +7338,13 @@ func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ServerId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ObjectId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ControlAddr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Addr", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5445,13 +7372,13 @@ func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ControlAddr = string(dAtA[iNdEx:postIndex])
+			m.Addr = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DataAddr", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
 			}
-			var stringLen uint64
+			m.Kind = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowController
@@ -5461,27 +7388,14 @@ func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Kind |= ReplicaFailureKind(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthController
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthController
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.DataAddr = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Zone", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Detail", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5509,46 +7423,8 @@ func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Zone = string(dAtA[iNdEx:postIndex])
+			m.Detail = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CapacityBytes", wireType)
-			}
-			m.CapacityBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowController
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CapacityBytes |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UsedBytes", wireType)
-			}
-			m.UsedBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowController
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.UsedBytes |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -5570,7 +7446,7 @@ func (m *HeartbeatBlobServerRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *HeartbeatBlobServerResponse) Unmarshal(dAtA []byte) error {
+func (m *ReportFailureResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5593,31 +7469,12 @@ func (m *HeartbeatBlobServerResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HeartbeatBlobServerResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReportFailureResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HeartbeatBlobServerResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReportFailureResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DiskId", wireType)
-			}
-			m.DiskId = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowController
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.DiskId |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipController(dAtA[iNdEx:])
@@ -5,7 +5,6 @@ import (
 
 	"github.com/cockroachdb/basaltclient/basaltpb"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // BlobControlClient provides gRPC access to blob server control operations
@@ -16,9 +15,24 @@ type BlobControlClient struct {
 	client basaltpb.BlobClient
 }
 
+// BlobControlResolver returns the control client for the blob server whose
+// data endpoint is at addr (as in basaltpb.ReplicaInfo.Addr). Callers that
+// accept a resolver do not close the clients it returns.
+type BlobControlResolver func(addr string) (*BlobControlClient, error)
+
+// blobController is the subset of BlobControlClient used by QuorumWriter and
+// RecoverQuorumPrefix.
+// This interface exists primarily for testing; production code uses
+// *BlobControlClient.
+type blobController interface {
+	Create(ctx context.Context, id ObjectID) error
+	Seal(ctx context.Context, id ObjectID) (int64, error)
+	Stat(ctx context.Context, id ObjectID) (int64, bool, error)
+}
+
 // NewBlobControlClient creates a new control client connected to the blob server at addr.
 func NewBlobControlClient(addr string) (*BlobControlClient, error) {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dialGRPC(addr)
 	if err != nil {
 		return nil, err
 	}
@@ -2,8 +2,13 @@ package basaltclient
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"io"
 	"net"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/cockroachdb/errors"
 )
@@ -16,15 +21,23 @@ import (
 // access, either by using a pool (which provides exclusive access via
 // acquire/release semantics) or by using a dedicated client per goroutine.
 type BlobDataClient struct {
-	addr   string
-	conn   net.Conn
-	r      *bufio.Reader
-	hdrBuf [RequestHeaderSize]byte // reusable buffer for request headers
+	addr string
+	// connMu serializes changes to conn with abort, which may be called from
+	// another goroutine. The goroutine using the client may read conn without
+	// holding connMu since it is the only one that changes it.
+	connMu  sync.Mutex
+	conn    net.Conn
+	aborted bool
+	timeout time.Duration // per-request deadline; 0 means none
+	r       *bufio.Reader
+	hdrBuf  [RequestHeaderSize]byte // reusable buffer for request headers
 	// ioBufs is a pre-allocated backing array for net.Buffers to avoid
 	// allocations when doing gather writes (writev). tmpBufs is a slice
 	// header that points to ioBufs, avoiding escape of a local slice header.
-	ioBufs  [2][]byte
+	ioBufs  [3][]byte
 	tmpBufs net.Buffers
+	// chainBuf is a reusable buffer for encoding chain headers.
+	chainBuf []byte
 }
 
 // NewBlobDataClient creates a new data client for the given server address.
@@ -33,8 +46,20 @@ func NewBlobDataClient(addr string) *BlobDataClient {
 	return &BlobDataClient{addr: addr}
 }
 
+// SetRequestTimeout sets a deadline for each subsequent request: if the
+// request and its response do not complete within d, the request fails with a
+// timeout error and the connection is closed. A d of 0 disables the deadline.
+func (c *BlobDataClient) SetRequestTimeout(d time.Duration) {
+	c.timeout = d
+	if d == 0 && c.conn != nil {
+		_ = c.conn.SetDeadline(time.Time{})
+	}
+}
+
 // Close closes the connection to the server.
 func (c *BlobDataClient) Close() error {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
 	if c.conn != nil {
 		err := c.conn.Close()
 		c.conn = nil
@@ -44,26 +69,87 @@ func (c *BlobDataClient) Close() error {
 	return nil
 }
 
-func (c *BlobDataClient) ensureConnected() error {
+// errClientAborted is returned by operations on a client after abort.
+var errClientAborted = errors.New("blob data client aborted")
+
+// abort closes the client's connection, failing any in-progress operation,
+// and prevents new connections from being established. Unlike Close, abort
+// may be called concurrently with an operation; it is used to force-close
+// clients that are still in use when a pool is drained.
+func (c *BlobDataClient) abort() {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	c.aborted = true
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+}
+
+func (c *BlobDataClient) ensureConnected(ctx context.Context) error {
 	if c.conn != nil {
 		return nil
 	}
-	conn, err := net.Dial("tcp", c.addr)
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
 	if err != nil {
 		return errors.Wrapf(err, "connecting to %s", c.addr)
 	}
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	if c.aborted {
+		_ = conn.Close()
+		return errClientAborted
+	}
 	c.conn = conn
 	c.r = bufio.NewReader(conn)
 	return nil
 }
 
-// doRequest sends a request and reads the response. src is the data to send
-// with the request (may be nil). dst is the buffer to read response data into
-// (may be nil if no response data is expected). Returns the number of bytes
-// read into dst.
-func (c *BlobDataClient) doRequest(hdr RequestHeader, src, dst []byte) (int, error) {
-	if err := c.ensureConnected(); err != nil {
-		return 0, err
+// Connection-level error markers. Errors returned by BlobDataClient operations
+// are marked with errConnFailed when the connection was closed as a result of
+// the failure, and additionally with errStaleConn when the failing connection
+// had been reused from a previous request and failed, other than by timing
+// out, before any response was received.
+// A stale connection usually means the server closed an idle connection, so
+// the request can be retried on a fresh connection.
+var (
+	errConnFailed = errors.New("blob data connection failed")
+	errStaleConn  = errors.New("stale blob data connection")
+)
+
+// failConn closes the connection after an I/O error and marks err so that
+// callers can distinguish connection failures from protocol status errors.
+// An expired request deadline is never marked stale, even on a reused
+// connection: the server may have received and applied the request and only
+// been slow to respond, so the request must not be retried blindly.
+func (c *BlobDataClient) failConn(err error, stale bool) error {
+	stale = stale && !errors.Is(err, os.ErrDeadlineExceeded)
+	c.connMu.Lock()
+	_ = c.conn.Close()
+	c.conn = nil
+	c.r = nil
+	c.connMu.Unlock()
+	err = errors.Mark(err, errConnFailed)
+	if stale {
+		err = errors.Mark(err, errStaleConn)
+	}
+	return err
+}
+
+// doRequest sends a request and reads the response. prefix and src are the
+// data to send with the request, in that order (either may be nil). dst is the
+// buffer to read response data into (may be nil if no response data is
+// expected). Returns the number of bytes read into dst.
+func (c *BlobDataClient) doRequest(hdr RequestHeader, prefix, src, dst []byte) (int, error) {
+	reused := c.conn != nil
+	if err := c.ensureConnected(context.Background()); err != nil {
+		return 0, errors.Mark(err, errConnFailed)
+	}
+
+	if c.timeout > 0 {
+		if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return 0, c.failConn(errors.Wrap(err, "setting deadline"), false)
+		}
 	}
 
 	// Encode header into our reusable buffer.
@@ -74,26 +160,23 @@ func (c *BlobDataClient) doRequest(hdr RequestHeader, src, dst []byte) (int, err
 	// We use the pre-allocated ioBufs array and tmpBufs slice header to avoid
 	// allocations. tmpBufs is a field so its address doesn't escape.
 	c.ioBufs[0] = c.hdrBuf[:]
+	c.tmpBufs = c.ioBufs[:1]
+	if len(prefix) > 0 {
+		c.tmpBufs = append(c.tmpBufs, prefix)
+	}
 	if len(src) > 0 {
-		c.ioBufs[1] = src
-		c.tmpBufs = c.ioBufs[:2]
-	} else {
-		c.tmpBufs = c.ioBufs[:1]
+		c.tmpBufs = append(c.tmpBufs, src)
 	}
 	_, err := c.tmpBufs.WriteTo(c.conn)
-	c.ioBufs[1] = nil // clear reference to data to allow GC
+	c.ioBufs[1], c.ioBufs[2] = nil, nil // clear references to data to allow GC
 	if err != nil {
-		_ = c.conn.Close()
-		c.conn = nil
-		return 0, errors.Wrap(err, "writing request")
+		return 0, c.failConn(errors.Wrap(err, "writing request"), reused)
 	}
 
 	// Read response header.
 	respHdr, err := ReadResponseHeader(c.r)
 	if err != nil {
-		_ = c.conn.Close()
-		c.conn = nil
-		return 0, err
+		return 0, c.failConn(err, reused)
 	}
 
 	// Read response data into dst if provided.
@@ -103,26 +186,20 @@ func (c *BlobDataClient) doRequest(hdr RequestHeader, src, dst []byte) (int, err
 			// Read directly into dst, up to its capacity.
 			readLen := min(respHdr.Length, uint64(len(dst)))
 			if _, err := io.ReadFull(c.r, dst[:readLen]); err != nil {
-				_ = c.conn.Close()
-				c.conn = nil
-				return 0, errors.Wrap(err, "reading response data")
+				return 0, c.failConn(errors.Wrap(err, "reading response data"), false)
 			}
 			n = int(readLen)
 			// Discard any extra bytes beyond dst capacity.
 			if respHdr.Length > uint64(len(dst)) {
 				extra := respHdr.Length - uint64(len(dst))
 				if _, err := io.CopyN(io.Discard, c.r, int64(extra)); err != nil {
-					_ = c.conn.Close()
-					c.conn = nil
-					return n, errors.Wrap(err, "discarding extra response data")
+					return n, c.failConn(errors.Wrap(err, "discarding extra response data"), false)
 				}
 			}
 		} else {
 			// No dst provided, discard response data.
 			if _, err := io.CopyN(io.Discard, c.r, int64(respHdr.Length)); err != nil {
-				_ = c.conn.Close()
-				c.conn = nil
-				return 0, errors.Wrap(err, "discarding response data")
+				return 0, c.failConn(errors.Wrap(err, "discarding response data"), false)
 			}
 		}
 	}
@@ -141,7 +218,7 @@ func (c *BlobDataClient) Append(id ObjectID, offset uint64, data []byte) error {
 		ObjectID: id,
 		Offset:   offset,
 		Length:   uint64(len(data)),
-	}, data, nil)
+	}, nil, data, nil)
 	return err
 }
 
@@ -152,10 +229,58 @@ func (c *BlobDataClient) AppendSync(id ObjectID, offset uint64, data []byte) err
 		ObjectID: id,
 		Offset:   offset,
 		Length:   uint64(len(data)),
-	}, data, nil)
+	}, nil, data, nil)
+	return err
+}
+
+// AppendChain appends data to an object and forwards it along the chain of
+// replicas at next, returning once every replica in the chain has
+// acknowledged. If sync is set, every replica syncs the data before
+// acknowledging. An empty data with sync set syncs the whole chain.
+//
+// If a downstream replica fails, AppendChain returns a *ChainError
+// identifying it, marked with ErrChainFailed. The replicas before it in the
+// chain, including this client's server, hold the data; those after it do
+// not.
+func (c *BlobDataClient) AppendChain(
+	id ObjectID, offset uint64, next []string, data []byte, sync bool,
+) error {
+	var err error
+	c.chainBuf, err = ChainHeader{Sync: sync, Next: next}.AppendEncode(c.chainBuf[:0])
+	if err != nil {
+		return err
+	}
+	var failed [1]byte
+	_, err = c.doRequest(RequestHeader{
+		OpCode:   OpAppendChain,
+		ObjectID: id,
+		Offset:   offset,
+		Length:   uint64(len(c.chainBuf) + len(data)),
+	}, c.chainBuf, data, failed[:])
+	if errors.Is(err, ErrChainFailed) {
+		// doRequest fills dst before checking the status.
+		if i := int(failed[0]); i < len(next) {
+			return errors.Mark(&ChainError{Index: i, Addr: next[i]}, ErrChainFailed)
+		}
+	}
 	return err
 }
 
+// ChainError is returned by AppendChain when a downstream replica in the
+// chain failed.
+type ChainError struct {
+	// Index is the index of the failed replica in the chain passed to
+	// AppendChain.
+	Index int
+	// Addr is the address of the failed replica.
+	Addr string
+}
+
+// Error implements error.
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("chain replica %d (%s) failed", e.Index, e.Addr)
+}
+
 // Sync syncs an object's data to disk.
 func (c *BlobDataClient) Sync(id ObjectID) error {
 	// Sync is implemented as AppendSync with empty data.
@@ -170,7 +295,7 @@ func (c *BlobDataClient) Read(id ObjectID, offset uint64, p []byte) (int, error)
 		ObjectID: id,
 		Offset:   offset,
 		Length:   uint64(len(p)),
-	}, nil, p)
+	}, nil, nil, p)
 }
 
 // Addr returns the server address this client connects to.
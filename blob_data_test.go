@@ -0,0 +1,90 @@
+package basaltclient
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestBlobDataClientRequestTimeout(t *testing.T) {
+	srv := newTestBlobServer(t)
+	stall := make(chan struct{})
+	defer close(stall)
+	srv.setOnRequest(func(hdr RequestHeader) {
+		if hdr.ObjectID == (ObjectID{1}) {
+			<-stall
+		}
+	})
+
+	c := NewBlobDataClient(srv.addr())
+	defer c.Close()
+	c.SetRequestTimeout(50 * time.Millisecond)
+
+	if err := c.AppendSync(ObjectID{2}, 0, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	err := c.AppendSync(ObjectID{1}, 0, []byte("data"))
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if !errors.Is(err, errConnFailed) {
+		t.Fatalf("expected connection failure, got %v", err)
+	}
+	// The request was sent on a reused connection, but the server may have
+	// applied it, so the error must not invite a retry.
+	if errors.Is(err, errStaleConn) {
+		t.Fatalf("expected timeout not to be marked stale, got %v", err)
+	}
+
+	// The client reconnects for the next request.
+	c.SetRequestTimeout(0)
+	if err := c.AppendSync(ObjectID{2}, 4, []byte("more")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBlobDataClientAppendChain(t *testing.T) {
+	srvs := []*testBlobServer{newTestBlobServer(t), newTestBlobServer(t), newTestBlobServer(t)}
+	contents := func(s *testBlobServer) string {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return string(s.objects[ObjectID{1}])
+	}
+
+	c := NewBlobDataClient(srvs[0].addr())
+	defer c.Close()
+	next := []string{srvs[1].addr(), srvs[2].addr()}
+	if err := c.AppendChain(ObjectID{1}, 0, next, []byte("hello"), true /* sync */); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range srvs {
+		if got := contents(s); got != "hello" {
+			t.Fatalf("server %d: expected %q, got %q", i, "hello", got)
+		}
+	}
+
+	// A failed tail is reported by index; the replicas before it hold the
+	// data.
+	srvs[2].close()
+	err := c.AppendChain(ObjectID{1}, 5, next, []byte(" world"), true /* sync */)
+	var chainErr *ChainError
+	if !errors.As(err, &chainErr) || !errors.Is(err, ErrChainFailed) {
+		t.Fatalf("expected chain error, got %v", err)
+	}
+	if chainErr.Index != 1 || chainErr.Addr != srvs[2].addr() {
+		t.Fatalf("expected replica 1 (%s) to fail, got %+v", srvs[2].addr(), chainErr)
+	}
+	for i, s := range srvs[:2] {
+		if got := contents(s); got != "hello world" {
+			t.Fatalf("server %d: expected %q, got %q", i, "hello world", got)
+		}
+	}
+
+	// The connection remains usable after a chain failure.
+	if err := c.AppendChain(ObjectID{1}, 11, next[:1], []byte("!"), false /* sync */); err != nil {
+		t.Fatal(err)
+	}
+}
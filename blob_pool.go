@@ -1,19 +1,38 @@
 package basaltclient
 
-import "sync"
+import (
+	"context"
+	"sync"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+)
 
 const defaultPoolSize = 8
 
+// ErrPoolClosed is returned by pool-level operations once the pool is closed.
+var ErrPoolClosed = errors.New("blob data client pool closed")
+
+// ErrInflightBytesExceeded is returned by pool-level operations when the
+// per-server in-flight byte budget is exhausted and the pool is configured to
+// fail fast (see WithBlobPoolFailFast).
+var ErrInflightBytesExceeded = errors.New("blob server in-flight byte budget exceeded")
+
 // BlobDataClientPool manages pooled connections to blob server data endpoints.
 // It maintains separate per-server pools and provides exclusive access to
 // clients via acquire/release semantics.
 //
 // BlobDataClientPool is safe for concurrent use from multiple goroutines.
 type BlobDataClientPool struct {
-	poolSize int
-	mu       sync.Mutex
-	pools    map[string]*serverPool
-	closed   bool
+	poolSize   int
+	maxBytes   int64 // per-server in-flight byte budget (0 = unlimited)
+	failFast   bool  // fail rather than block when maxBytes is exhausted
+	autoWarm   int   // connections to establish when a server is first observed
+	warmCtx    context.Context
+	cancelWarm context.CancelFunc
+	mu         sync.Mutex
+	pools      map[string]*serverPool
+	closed     bool
 }
 
 // BlobDataClientPoolOption configures a BlobDataClientPool.
@@ -29,15 +48,59 @@ func WithBlobPoolSize(size int) BlobDataClientPoolOption {
 	}
 }
 
+// WithBlobPoolMaxInflightBytes limits the number of bytes that may be in
+// flight to a single blob server across the pool-level operations (Read,
+// Append and AppendSync). An operation that would exceed the budget blocks
+// until enough in-flight operations complete. A single operation larger than
+// the budget is admitted once nothing else is in flight. Clients obtained via
+// Acquire are not subject to the budget. The default is 0 (unlimited).
+func WithBlobPoolMaxInflightBytes(n int64) BlobDataClientPoolOption {
+	return func(p *BlobDataClientPool) {
+		if n > 0 {
+			p.maxBytes = n
+		}
+	}
+}
+
+// WithBlobPoolFailFast makes pool-level operations return
+// ErrInflightBytesExceeded instead of blocking when the in-flight byte budget
+// set by WithBlobPoolMaxInflightBytes is exhausted.
+func WithBlobPoolFailFast() BlobDataClientPoolOption {
+	return func(p *BlobDataClientPool) {
+		p.failFast = true
+	}
+}
+
+// WithBlobPoolAutoWarm makes ObserveReplicas top up the pool for each observed
+// blob server to n connections in the background. n is capped at the pool
+// size. The default is 0 (no automatic warming).
+func WithBlobPoolAutoWarm(n int) BlobDataClientPoolOption {
+	return func(p *BlobDataClientPool) {
+		if n > 0 {
+			p.autoWarm = n
+		}
+	}
+}
+
 // serverPool manages a pool of BlobDataClient connections to a single server.
 type serverPool struct {
 	addr     string
 	poolSize int
+	maxBytes int64
+	failFast bool
 	mu       sync.Mutex
 	cond     *sync.Cond
-	clients  []*BlobDataClient // available clients (LIFO stack)
-	count    int               // total created (available + in-use)
+	clients  []*BlobDataClient            // available clients (LIFO stack)
+	inUse    map[*BlobDataClient]struct{} // clients handed out by acquire
+	count    int                          // total created (available + in-use)
 	closed   bool
+	warming  bool // a background warm started by ObserveReplicas is running
+
+	// bytesCond is signaled when in-flight bytes are released. It is separate
+	// from cond so that releasing bytes does not consume wakeups intended for
+	// goroutines waiting for a client, and vice versa.
+	bytesCond *sync.Cond
+	bytes     int64 // bytes currently in flight through pool-level operations
 }
 
 // NewBlobDataClientPool creates a new data client pool.
@@ -49,6 +112,7 @@ func NewBlobDataClientPool(opts ...BlobDataClientPoolOption) *BlobDataClientPool
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.warmCtx, p.cancelWarm = context.WithCancel(context.Background())
 	return p
 }
 
@@ -56,19 +120,84 @@ func NewBlobDataClientPool(opts ...BlobDataClientPoolOption) *BlobDataClientPool
 // in the pool are in use, Acquire blocks until one becomes available.
 // The returned client must be released via Release or ReleaseWithError.
 func (p *BlobDataClientPool) Acquire(addr string) *BlobDataClient {
+	sp := p.serverPool(addr)
+	if sp == nil {
+		return nil
+	}
+	client, _ := sp.acquire(context.Background())
+	return client
+}
+
+// serverPool returns the pool for addr, creating it if necessary. Returns nil
+// if the pool is closed.
+func (p *BlobDataClientPool) serverPool(addr string) *serverPool {
 	p.mu.Lock()
+	defer p.mu.Unlock()
 	if p.closed {
-		p.mu.Unlock()
 		return nil
 	}
 	sp := p.pools[addr]
 	if sp == nil {
-		sp = newServerPool(addr, p.poolSize)
+		sp = newServerPool(addr, p.poolSize, p.maxBytes, p.failFast)
 		p.pools[addr] = sp
 	}
-	p.mu.Unlock()
+	return sp
+}
+
+// Warm establishes connections to each of the given servers ahead of time so
+// that the first operations against them do not pay the connection handshake.
+// Up to n connections are created per server, capped at the pool size;
+// existing connections (idle or in use) count towards n. Servers are warmed
+// concurrently, and errors for any servers that could not be reached are
+// combined into the returned error.
+func (p *BlobDataClientPool) Warm(ctx context.Context, addrs []string, n int) error {
+	pools := make([]*serverPool, len(addrs))
+	for i, addr := range addrs {
+		if pools[i] = p.serverPool(addr); pools[i] == nil {
+			return ErrPoolClosed
+		}
+	}
 
-	return sp.acquire()
+	errs := make([]error, len(pools))
+	var wg sync.WaitGroup
+	for i, sp := range pools {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = sp.warm(ctx, n)
+		}()
+	}
+	wg.Wait()
+
+	var err error
+	for _, e := range errs {
+		err = errors.CombineErrors(err, e)
+	}
+	return err
+}
+
+// ObserveReplicas informs the pool of the blob servers holding an object's
+// replicas, typically taken from ObjectMeta.Replicas. If automatic warming is
+// enabled (see WithBlobPoolAutoWarm), the pool for each server that holds
+// fewer connections than the warming target is topped up in the background,
+// whether the server is new or its connections were lost since. Warming
+// failures are ignored; connections are then established lazily on first use.
+func (p *BlobDataClientPool) ObserveReplicas(replicas []basaltpb.ReplicaInfo) {
+	if p.autoWarm == 0 {
+		return
+	}
+	for _, r := range replicas {
+		sp := p.serverPool(r.Addr)
+		if sp == nil {
+			return
+		}
+		if sp.startWarming(p.autoWarm) {
+			go func() {
+				_ = sp.warm(p.warmCtx, p.autoWarm)
+				sp.finishWarming()
+			}()
+		}
+	}
 }
 
 // Release returns a healthy client to the pool for reuse. The client must
@@ -103,56 +232,238 @@ func (p *BlobDataClientPool) ReleaseWithError(client *BlobDataClient) {
 	}
 }
 
-// Close closes all connections in all pools and prevents new acquisitions.
+// Close closes all idle connections in all pools and prevents new
+// acquisitions. Clients that are still in use are closed when they are
+// released. Use Drain to wait for in-use clients.
 func (p *BlobDataClientPool) Close() error {
-	p.mu.Lock()
-	if p.closed {
-		p.mu.Unlock()
+	pools, alreadyClosed := p.markClosed()
+	if alreadyClosed {
 		return nil
 	}
-	p.closed = true
-	pools := p.pools
-	p.pools = nil
-	p.mu.Unlock()
-
 	for _, sp := range pools {
 		sp.close()
 	}
 	return nil
 }
 
+// DrainSummary describes the clients that were still in use when Drain gave
+// up waiting for them.
+type DrainSummary struct {
+	// Outstanding maps blob server addresses to the number of in-use clients
+	// that were force-closed. Empty if all clients were released in time.
+	Outstanding map[string]int
+}
+
+// Drain gracefully shuts down the pool. It stops new acquisitions, closes idle
+// clients, and waits for in-use clients to be released. If ctx is done before
+// all clients are released, the remaining clients are force-closed, failing
+// any operations in progress on them, and Drain returns ctx.Err() along with
+// a summary of the clients that were still outstanding.
+//
+// Drain may be called after Close, for example to wait for clients that were
+// in use when the pool was closed.
+func (p *BlobDataClientPool) Drain(ctx context.Context) (DrainSummary, error) {
+	pools, _ := p.markClosed()
+	for _, sp := range pools {
+		sp.close()
+	}
+	for _, sp := range pools {
+		sp.waitReleased(ctx)
+	}
+
+	summary := DrainSummary{Outstanding: make(map[string]int)}
+	for _, sp := range pools {
+		if n := sp.abortInUse(); n > 0 {
+			summary.Outstanding[sp.addr] = n
+		}
+	}
+	if len(summary.Outstanding) > 0 {
+		return summary, ctx.Err()
+	}
+	return summary, nil
+}
+
+// markClosed marks the pool as closed and returns the server pools.
+// Returns true if already closed.
+func (p *BlobDataClientPool) markClosed() ([]*serverPool, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Server pools are retained after closing so that clients released
+	// later are closed rather than leaked.
+	pools := make([]*serverPool, 0, len(p.pools))
+	for _, sp := range p.pools {
+		pools = append(pools, sp)
+	}
+	if p.closed {
+		return pools, true
+	}
+	p.closed = true
+
+	// Abandon any background warming.
+	p.cancelWarm()
+	return pools, false
+}
+
+// Read reads from an object on the blob server at addr into buf, returning the
+// number of bytes read. It acquires a client from the pool for the duration
+// of the call and releases it afterwards.
+func (p *BlobDataClientPool) Read(
+	ctx context.Context, addr string, id ObjectID, offset uint64, buf []byte,
+) (int, error) {
+	var n int
+	err := p.do(ctx, addr, int64(len(buf)), func(c *BlobDataClient) error {
+		var err error
+		n, err = c.Read(id, offset, buf)
+		return err
+	})
+	return n, err
+}
+
+// Append appends data to an object on the blob server at addr. It acquires a
+// client from the pool for the duration of the call and releases it
+// afterwards.
+func (p *BlobDataClientPool) Append(
+	ctx context.Context, addr string, id ObjectID, offset uint64, data []byte,
+) error {
+	return p.do(ctx, addr, int64(len(data)), func(c *BlobDataClient) error {
+		return c.Append(id, offset, data)
+	})
+}
+
+// AppendSync appends data to an object on the blob server at addr and syncs
+// it to disk. It acquires a client from the pool for the duration of the call
+// and releases it afterwards.
+func (p *BlobDataClientPool) AppendSync(
+	ctx context.Context, addr string, id ObjectID, offset uint64, data []byte,
+) error {
+	return p.do(ctx, addr, int64(len(data)), func(c *BlobDataClient) error {
+		return c.AppendSync(id, offset, data)
+	})
+}
+
+// AppendChain appends data to an object on the blob server at addr and has it
+// forwarded along the chain of replicas at next (see
+// BlobDataClient.AppendChain). It acquires a client from the pool for the
+// duration of the call and releases it afterwards.
+func (p *BlobDataClientPool) AppendChain(
+	ctx context.Context, addr string, id ObjectID, offset uint64, next []string, data []byte, sync bool,
+) error {
+	return p.do(ctx, addr, int64(len(data)), func(c *BlobDataClient) error {
+		return c.AppendChain(id, offset, next, data, sync)
+	})
+}
+
+// Sync syncs an object on the blob server at addr to disk. It acquires a
+// client from the pool for the duration of the call and releases it
+// afterwards.
+func (p *BlobDataClientPool) Sync(ctx context.Context, addr string, id ObjectID) error {
+	return p.do(ctx, addr, 0, func(c *BlobDataClient) error {
+		return c.Sync(id)
+	})
+}
+
+// do reserves n bytes of the in-flight budget for addr, acquires a client,
+// runs fn, and releases the client, closing it if fn failed with a connection
+// error. If fn fails because a pooled
+// connection had gone stale (e.g. the server closed it while idle), fn is
+// retried once on a fresh connection.
+//
+// Note that an append retried after a stale connection error may already
+// have been applied by the server, in which case the retry fails with a
+// protocol error rather than silently appending twice.
+func (p *BlobDataClientPool) do(
+	ctx context.Context, addr string, n int64, fn func(*BlobDataClient) error,
+) error {
+	sp := p.serverPool(addr)
+	if sp == nil {
+		return ErrPoolClosed
+	}
+	if err := sp.acquireBytes(ctx, n); err != nil {
+		return err
+	}
+	defer sp.releaseBytes(n)
+
+	for attempt := 0; ; attempt++ {
+		client, err := sp.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		if client == nil {
+			return ErrPoolClosed
+		}
+		if attempt > 0 {
+			// Force a new connection rather than reusing another idle one
+			// that may have gone stale for the same reason.
+			_ = client.Close()
+		}
+
+		err = fn(client)
+		if err == nil || !errors.Is(err, errConnFailed) {
+			// Protocol status errors leave the connection usable.
+			sp.release(client)
+			return err
+		}
+		sp.releaseWithError(client)
+		if attempt > 0 || !errors.Is(err, errStaleConn) {
+			return err
+		}
+	}
+}
+
 // newServerPool creates a new server pool for the given address.
-func newServerPool(addr string, poolSize int) *serverPool {
+func newServerPool(addr string, poolSize int, maxBytes int64, failFast bool) *serverPool {
 	sp := &serverPool{
 		addr:     addr,
 		poolSize: poolSize,
+		maxBytes: maxBytes,
+		failFast: failFast,
 		clients:  make([]*BlobDataClient, 0, poolSize),
+		inUse:    make(map[*BlobDataClient]struct{}),
 	}
 	sp.cond = sync.NewCond(&sp.mu)
+	sp.bytesCond = sync.NewCond(&sp.mu)
 	return sp
 }
 
 // acquire returns a BlobDataClient from the pool, blocking if necessary.
-func (sp *serverPool) acquire() *BlobDataClient {
+// Returns (nil, nil) if the pool is closed, or ctx.Err() if ctx is done
+// before a client becomes available.
+func (sp *serverPool) acquire(ctx context.Context) (*BlobDataClient, error) {
 	sp.mu.Lock()
 	defer sp.mu.Unlock()
 
+	// Wake up the wait below if ctx is canceled. The lock ensures the
+	// broadcast cannot be lost between the ctx check and cond.Wait.
+	stop := context.AfterFunc(ctx, func() {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		sp.cond.Broadcast()
+	})
+	defer stop()
+
 	for {
 		if sp.closed {
-			return nil
+			return nil, nil
+		}
+		if err := ctx.Err(); err != nil {
+			// We may have consumed a release signal intended for another
+			// waiter; pass it on.
+			sp.cond.Signal()
+			return nil, err
 		}
 
 		// If there's an available client, return it (LIFO).
 		if len(sp.clients) > 0 {
 			client := sp.clients[len(sp.clients)-1]
 			sp.clients = sp.clients[:len(sp.clients)-1]
-			return client
+			sp.inUse[client] = struct{}{}
+			return client, nil
 		}
 
 		// If we haven't reached the pool size limit, create a new client.
 		if sp.count < sp.poolSize {
-			sp.count++
-			return NewBlobDataClient(sp.addr)
+			return sp.newClientLocked(), nil
 		}
 
 		// Pool is at capacity, wait for a client to be released.
@@ -160,13 +471,120 @@ func (sp *serverPool) acquire() *BlobDataClient {
 	}
 }
 
-// release returns a healthy client to the pool for reuse.
+// acquireBytes reserves n bytes of the in-flight budget, blocking until they
+// are available unless the pool is configured to fail fast.
+func (sp *serverPool) acquireBytes(ctx context.Context, n int64) error {
+	if sp.maxBytes == 0 || n == 0 {
+		return nil
+	}
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		sp.bytesCond.Broadcast()
+	})
+	defer stop()
+
+	for {
+		if sp.closed {
+			return ErrPoolClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Admit an oversized request when nothing else is in flight, so that
+		// it cannot wait forever.
+		if sp.bytes+n <= sp.maxBytes || sp.bytes == 0 {
+			sp.bytes += n
+			return nil
+		}
+		if sp.failFast {
+			return ErrInflightBytesExceeded
+		}
+		sp.bytesCond.Wait()
+	}
+}
+
+// releaseBytes returns n bytes to the in-flight budget.
+func (sp *serverPool) releaseBytes(n int64) {
+	if sp.maxBytes == 0 || n == 0 {
+		return
+	}
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.bytes -= n
+	// Waiters may need different amounts, so wake them all.
+	sp.bytesCond.Broadcast()
+}
+
+// newClientLocked creates a new in-use client, taking up a slot in the pool.
+// sp.mu must be held.
+func (sp *serverPool) newClientLocked() *BlobDataClient {
+	client := NewBlobDataClient(sp.addr)
+	sp.count++
+	sp.inUse[client] = struct{}{}
+	return client
+}
+
+// warm creates and connects clients until the pool holds at least n of them
+// (capped at the pool size), adding them to the available stack.
+func (sp *serverPool) warm(ctx context.Context, n int) error {
+	for {
+		sp.mu.Lock()
+		if sp.closed || sp.count >= min(n, sp.poolSize) {
+			sp.mu.Unlock()
+			return nil
+		}
+		// Reserve the slot before connecting outside the lock.
+		client := sp.newClientLocked()
+		sp.mu.Unlock()
+
+		if err := client.ensureConnected(ctx); err != nil {
+			sp.releaseWithError(client)
+			return err
+		}
+		sp.release(client)
+	}
+}
+
+// startWarming reports whether a background warm to n connections should be
+// started: the pool holds fewer than n connections (capped at the pool size)
+// and no background warm is already running. If it returns true, the caller
+// must call finishWarming once the warm completes.
+func (sp *serverPool) startWarming(n int) bool {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.closed || sp.warming || sp.count >= min(n, sp.poolSize) {
+		return false
+	}
+	sp.warming = true
+	return true
+}
+
+// finishWarming marks the background warm started by startWarming as done.
+func (sp *serverPool) finishWarming() {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.warming = false
+}
+
+// release returns a healthy client to the pool for reuse. Releasing a client
+// that is not in use (e.g. a double release) is a no-op.
 func (sp *serverPool) release(client *BlobDataClient) {
 	sp.mu.Lock()
 	defer sp.mu.Unlock()
 
+	if _, ok := sp.inUse[client]; !ok {
+		return
+	}
+	delete(sp.inUse, client)
+
 	if sp.closed {
 		_ = client.Close()
+		sp.count--
+		sp.cond.Broadcast()
 		return
 	}
 
@@ -176,18 +594,29 @@ func (sp *serverPool) release(client *BlobDataClient) {
 }
 
 // releaseWithError closes the client and frees the slot for a new connection.
+// Releasing a client that is not in use (e.g. a double release) is a no-op.
 func (sp *serverPool) releaseWithError(client *BlobDataClient) {
-	_ = client.Close()
-
 	sp.mu.Lock()
 	defer sp.mu.Unlock()
 
-	// Decrement count to free the slot for a new connection.
+	if _, ok := sp.inUse[client]; !ok {
+		return
+	}
+	delete(sp.inUse, client)
+	_ = client.Close()
+
+	// Decrement count to free the slot for a new connection. Broadcast when
+	// closed so that drain observes the pool becoming empty.
 	sp.count--
-	sp.cond.Signal()
+	if sp.closed {
+		sp.cond.Broadcast()
+	} else {
+		sp.cond.Signal()
+	}
 }
 
-// close closes all clients in the pool and wakes up any waiting goroutines.
+// close closes all idle clients in the pool and wakes up any waiting
+// goroutines. Clients that are in use are closed when they are released.
 func (sp *serverPool) close() {
 	sp.mu.Lock()
 	defer sp.mu.Unlock()
@@ -196,6 +625,39 @@ func (sp *serverPool) close() {
 	for _, client := range sp.clients {
 		_ = client.Close()
 	}
+	sp.count -= len(sp.clients)
 	sp.clients = nil
 	sp.cond.Broadcast()
+	sp.bytesCond.Broadcast()
+}
+
+// waitReleased waits until all in-use clients have been released, or ctx is
+// done. The pool must be closed.
+func (sp *serverPool) waitReleased(ctx context.Context) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		sp.cond.Broadcast()
+	})
+	defer stop()
+
+	for len(sp.inUse) > 0 && ctx.Err() == nil {
+		sp.cond.Wait()
+	}
+}
+
+// abortInUse force-closes all clients that are still in use and returns how
+// many there were. The clients remain in use until their holders release
+// them, but any in-progress or subsequent operation on them fails.
+func (sp *serverPool) abortInUse() int {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	for client := range sp.inUse {
+		client.abort()
+	}
+	return len(sp.inUse)
 }
@@ -1,10 +1,18 @@
 package basaltclient
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
 )
 
 func TestBlobDataClientPool_AcquireRelease(t *testing.T) {
@@ -257,3 +265,539 @@ func TestBlobDataClientPool_InvalidPoolSize(t *testing.T) {
 		t.Fatalf("expected default pool size %d for size=-5, got %d", defaultPoolSize, pool2.poolSize)
 	}
 }
+
+// testBlobServer is a minimal in-memory implementation of the blob data
+// protocol used to exercise clients against a real TCP connection.
+type testBlobServer struct {
+	ln net.Listener
+
+	mu        sync.Mutex
+	onRequest func(RequestHeader) // if set, called before each request is handled
+	objects   map[ObjectID][]byte
+	conns     map[net.Conn]struct{}
+	wg        sync.WaitGroup
+}
+
+func newTestBlobServer(t *testing.T) *testBlobServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testBlobServer{
+		ln:      ln,
+		objects: make(map[ObjectID][]byte),
+		conns:   make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.accept()
+	t.Cleanup(s.close)
+	return s
+}
+
+func (s *testBlobServer) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *testBlobServer) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+func (s *testBlobServer) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	for {
+		hdr, err := ReadRequestHeader(r)
+		if err != nil {
+			return
+		}
+		var data []byte
+		if hdr.OpCode != OpRead {
+			data = make([]byte, hdr.Length)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+		}
+		s.mu.Lock()
+		onRequest := s.onRequest
+		s.mu.Unlock()
+		if onRequest != nil {
+			onRequest(hdr)
+		}
+		var status StatusCode
+		var resp []byte
+		if hdr.OpCode == OpAppendChain {
+			status, resp = s.handleChain(hdr, data)
+		} else {
+			status, resp = s.handle(hdr, data)
+		}
+		if err := WriteResponse(conn, status, resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *testBlobServer) handle(hdr RequestHeader, data []byte) (StatusCode, []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.objects[hdr.ObjectID]
+	switch hdr.OpCode {
+	case OpAppend, OpAppendSync:
+		if len(data) == 0 {
+			return StatusOK, nil
+		}
+		if hdr.Offset != uint64(len(obj)) {
+			return StatusBadRequest, nil
+		}
+		s.objects[hdr.ObjectID] = append(obj, data...)
+		return StatusOK, nil
+	case OpRead:
+		if obj == nil {
+			return StatusNotFound, nil
+		}
+		if hdr.Offset > uint64(len(obj)) {
+			return StatusBadRequest, nil
+		}
+		end := min(hdr.Offset+hdr.Length, uint64(len(obj)))
+		return StatusOK, append([]byte(nil), obj[hdr.Offset:end]...)
+	default:
+		return StatusInvalidOp, nil
+	}
+}
+
+// handleChain appends the data of an OpAppendChain request locally and then
+// forwards it to the rest of the chain.
+func (s *testBlobServer) handleChain(hdr RequestHeader, payload []byte) (StatusCode, []byte) {
+	chain, n, err := DecodeChainHeader(payload)
+	if err != nil {
+		return StatusBadRequest, nil
+	}
+	data := payload[n:]
+	hdr.OpCode = OpAppendSync
+	if status, _ := s.handle(hdr, data); status != StatusOK || len(chain.Next) == 0 {
+		return status, nil
+	}
+	c := NewBlobDataClient(chain.Next[0])
+	defer func() { _ = c.Close() }()
+	err = c.AppendChain(hdr.ObjectID, hdr.Offset, chain.Next[1:], data, chain.Sync)
+	var chainErr *ChainError
+	switch {
+	case err == nil:
+		return StatusOK, nil
+	case errors.As(err, &chainErr):
+		return StatusChainFailed, []byte{byte(chainErr.Index + 1)}
+	default:
+		return StatusChainFailed, []byte{0}
+	}
+}
+
+func (s *testBlobServer) setOnRequest(fn func(RequestHeader)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRequest = fn
+}
+
+// dropConns closes all open server-side connections, leaving any pooled
+// client connections stale.
+func (s *testBlobServer) dropConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+func (s *testBlobServer) close() {
+	_ = s.ln.Close()
+	s.dropConns()
+	s.wg.Wait()
+}
+
+func TestBlobDataClientPool_OneShotOps(t *testing.T) {
+	srv := newTestBlobServer(t)
+	pool := NewBlobDataClientPool(WithBlobPoolSize(1))
+	defer pool.Close()
+
+	ctx := context.Background()
+	id := ObjectID{1}
+	if err := pool.Append(ctx, srv.addr(), id, 0, []byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.AppendSync(ctx, srv.addr(), id, 6, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Sync(ctx, srv.addr(), id); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 11)
+	n, err := pool.Read(ctx, srv.addr(), id, 0, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != "hello world" {
+		t.Fatalf("expected %q, got %q", "hello world", got)
+	}
+
+	// Protocol errors are returned without discarding the connection.
+	if _, err := pool.Read(ctx, srv.addr(), ObjectID{2}, 0, buf); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// With a pool size of 1, every operation must have released its client.
+	client := pool.Acquire(srv.addr())
+	if client == nil {
+		t.Fatal("expected non-nil client")
+	}
+	pool.Release(client)
+}
+
+func TestBlobDataClientPool_OneShotRetriesStaleConn(t *testing.T) {
+	srv := newTestBlobServer(t)
+	pool := NewBlobDataClientPool(WithBlobPoolSize(1))
+	defer pool.Close()
+
+	ctx := context.Background()
+	id := ObjectID{1}
+	if err := pool.AppendSync(ctx, srv.addr(), id, 0, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close the pooled connection from the server side. The next operation
+	// fails on the stale connection and is retried on a fresh one.
+	srv.dropConns()
+	buf := make([]byte, 4)
+	n, err := pool.Read(ctx, srv.addr(), id, 0, buf)
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if got := string(buf[:n]); got != "data" {
+		t.Fatalf("expected %q, got %q", "data", got)
+	}
+}
+
+func TestBlobDataClientPool_OneShotContext(t *testing.T) {
+	pool := NewBlobDataClientPool(WithBlobPoolSize(1))
+
+	addr := "localhost:26259"
+	client := pool.Acquire(addr)
+
+	// The only client is in use, so the operation blocks until ctx expires.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := pool.Sync(ctx, addr, ObjectID{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	pool.Release(client)
+
+	pool.Close()
+	if err := pool.Sync(context.Background(), addr, ObjectID{}); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestBlobDataClientPool_Warm(t *testing.T) {
+	srv := newTestBlobServer(t)
+	pool := NewBlobDataClientPool(WithBlobPoolSize(2))
+	defer pool.Close()
+
+	// Requesting more connections than the pool size is capped.
+	if err := pool.Warm(context.Background(), []string{srv.addr()}, 3); err != nil {
+		t.Fatal(err)
+	}
+	waitForConns(t, srv, 2)
+
+	// Warmed clients are handed out already connected.
+	c1 := pool.Acquire(srv.addr())
+	c2 := pool.Acquire(srv.addr())
+	if c1.conn == nil || c2.conn == nil {
+		t.Fatal("expected warmed clients to be connected")
+	}
+	pool.Release(c1)
+	pool.Release(c2)
+
+	// Warming again is a no-op since the pool is already full.
+	if err := pool.Warm(context.Background(), []string{srv.addr()}, 2); err != nil {
+		t.Fatal(err)
+	}
+	waitForConns(t, srv, 2)
+}
+
+func TestBlobDataClientPool_WarmError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	pool := NewBlobDataClientPool(WithBlobPoolSize(1))
+	defer pool.Close()
+	if err := pool.Warm(context.Background(), []string{addr}, 1); err == nil {
+		t.Fatal("expected error warming unreachable server")
+	}
+
+	// The failed connection must not hold the pool's only slot.
+	client := pool.Acquire(addr)
+	if client == nil {
+		t.Fatal("expected non-nil client")
+	}
+	pool.Release(client)
+}
+
+func TestBlobDataClientPool_ObserveReplicasAutoWarm(t *testing.T) {
+	srv := newTestBlobServer(t)
+	pool := NewBlobDataClientPool(WithBlobPoolSize(4), WithBlobPoolAutoWarm(2))
+	defer pool.Close()
+
+	replicas := []basaltpb.ReplicaInfo{{Addr: srv.addr()}}
+	pool.ObserveReplicas(replicas)
+	waitForConns(t, srv, 2)
+	sp := pool.serverPool(srv.addr())
+	waitFor(t, func() error {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		if idle := len(sp.clients); idle != 2 {
+			return errors.Newf("expected 2 idle clients, got %d", idle)
+		}
+		return nil
+	})
+
+	// Observing a server whose pool is already at the target is a no-op.
+	pool.ObserveReplicas(replicas)
+	time.Sleep(50 * time.Millisecond)
+	waitForConns(t, srv, 2)
+
+	// A server whose connections were lost is warmed back up to the target.
+	c := pool.Acquire(srv.addr())
+	pool.ReleaseWithError(c)
+	waitForConns(t, srv, 1)
+	pool.ObserveReplicas(replicas)
+	waitForConns(t, srv, 2)
+
+	// So is a server first used through Acquire rather than ObserveReplicas.
+	srv2 := newTestBlobServer(t)
+	c = pool.Acquire(srv2.addr())
+	if err := c.Sync(ObjectID{}); err != nil {
+		t.Fatal(err)
+	}
+	pool.Release(c)
+	waitForConns(t, srv2, 1)
+	pool.ObserveReplicas([]basaltpb.ReplicaInfo{{Addr: srv2.addr()}})
+	waitForConns(t, srv2, 2)
+}
+
+// waitForConns waits until the server has exactly n open connections.
+func waitForConns(t *testing.T, srv *testBlobServer, n int) {
+	t.Helper()
+	waitFor(t, func() error {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		if got := len(srv.conns); got != n {
+			return errors.Newf("expected %d server connections, got %d", n, got)
+		}
+		return nil
+	})
+}
+
+func TestBlobDataClientPool_DoubleRelease(t *testing.T) {
+	pool := NewBlobDataClientPool(WithBlobPoolSize(2))
+	defer pool.Close()
+
+	addr := "localhost:26259"
+	client := pool.Acquire(addr)
+	pool.Release(client)
+	// A second release must not put the client on the available stack twice.
+	pool.Release(client)
+	pool.ReleaseWithError(client)
+
+	c1 := pool.Acquire(addr)
+	c2 := pool.Acquire(addr)
+	if c1 == c2 {
+		t.Fatal("expected distinct clients after double release")
+	}
+	pool.Release(c1)
+	pool.Release(c2)
+}
+
+func TestBlobDataClientPool_ReleaseAfterClose(t *testing.T) {
+	srv := newTestBlobServer(t)
+	pool := NewBlobDataClientPool()
+
+	client := pool.Acquire(srv.addr())
+	if _, err := client.Read(ObjectID{}, 0, nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	waitForConns(t, srv, 1)
+	pool.Close()
+
+	// Releasing a client after close closes its connection.
+	pool.Release(client)
+	waitForConns(t, srv, 0)
+}
+
+func TestBlobDataClientPool_Drain(t *testing.T) {
+	pool := NewBlobDataClientPool(WithBlobPoolSize(2))
+
+	addr := "localhost:26259"
+	idle := pool.Acquire(addr)
+	pool.Release(idle)
+	client := pool.Acquire(addr)
+
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		pool.Release(client)
+		close(released)
+	}()
+
+	summary, err := pool.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-released:
+	default:
+		t.Fatal("Drain returned before the in-use client was released")
+	}
+	if len(summary.Outstanding) != 0 {
+		t.Fatalf("expected no outstanding clients, got %v", summary.Outstanding)
+	}
+	if c := pool.Acquire(addr); c != nil {
+		t.Fatal("expected nil client after drain")
+	}
+}
+
+func TestBlobDataClientPool_DrainForceCloses(t *testing.T) {
+	srv := newTestBlobServer(t)
+	pool := NewBlobDataClientPool()
+
+	client := pool.Acquire(srv.addr())
+	if _, err := client.Read(ObjectID{}, 0, nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	summary, err := pool.Drain(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if n := summary.Outstanding[srv.addr()]; n != 1 {
+		t.Fatalf("expected 1 outstanding client, got %d", n)
+	}
+	waitForConns(t, srv, 0)
+
+	// The straggler's operations fail and it cannot reconnect.
+	if _, err := client.Read(ObjectID{}, 0, nil); err == nil {
+		t.Fatal("expected error using force-closed client")
+	}
+	if _, err := client.Read(ObjectID{}, 0, nil); !errors.Is(err, errClientAborted) {
+		t.Fatalf("expected errClientAborted, got %v", err)
+	}
+	pool.ReleaseWithError(client)
+}
+
+func TestBlobDataClientPool_InflightBytes(t *testing.T) {
+	for _, failFast := range []bool{false, true} {
+		t.Run(fmt.Sprintf("failFast=%t", failFast), func(t *testing.T) {
+			srv := newTestBlobServer(t)
+			stall := make(chan struct{})
+			srv.setOnRequest(func(hdr RequestHeader) {
+				if hdr.ObjectID == (ObjectID{1}) {
+					<-stall
+				}
+			})
+
+			opts := []BlobDataClientPoolOption{WithBlobPoolMaxInflightBytes(100)}
+			if failFast {
+				opts = append(opts, WithBlobPoolFailFast())
+			}
+			pool := NewBlobDataClientPool(opts...)
+			defer pool.Close()
+			ctx := context.Background()
+
+			// Occupy 80 bytes of the budget with a stalled append.
+			first := make(chan error, 1)
+			go func() {
+				first <- pool.AppendSync(ctx, srv.addr(), ObjectID{1}, 0, make([]byte, 80))
+			}()
+			waitForInflightBytes(t, pool, srv.addr(), 80)
+
+			// Small operations still fit in the budget.
+			if err := pool.AppendSync(ctx, srv.addr(), ObjectID{2}, 0, make([]byte, 20)); err != nil {
+				t.Fatal(err)
+			}
+
+			// A 40 byte append does not fit.
+			second := make(chan error, 1)
+			go func() {
+				second <- pool.AppendSync(ctx, srv.addr(), ObjectID{3}, 0, make([]byte, 40))
+			}()
+			if failFast {
+				if err := <-second; !errors.Is(err, ErrInflightBytesExceeded) {
+					t.Fatalf("expected ErrInflightBytesExceeded, got %v", err)
+				}
+			} else {
+				select {
+				case err := <-second:
+					t.Fatalf("expected append to block, got %v", err)
+				case <-time.After(50 * time.Millisecond):
+				}
+			}
+
+			close(stall)
+			if err := <-first; err != nil {
+				t.Fatal(err)
+			}
+			if !failFast {
+				if err := <-second; err != nil {
+					t.Fatal(err)
+				}
+			}
+			waitForInflightBytes(t, pool, srv.addr(), 0)
+		})
+	}
+}
+
+func TestBlobDataClientPool_InflightBytesOversized(t *testing.T) {
+	srv := newTestBlobServer(t)
+	pool := NewBlobDataClientPool(WithBlobPoolMaxInflightBytes(10))
+	defer pool.Close()
+
+	// An operation larger than the whole budget is admitted when nothing
+	// else is in flight.
+	if err := pool.AppendSync(context.Background(), srv.addr(), ObjectID{1}, 0, make([]byte, 64)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForInflightBytes waits until the pool for addr has n bytes in flight.
+func waitForInflightBytes(t *testing.T, pool *BlobDataClientPool, addr string, n int64) {
+	t.Helper()
+	sp := pool.serverPool(addr)
+	waitFor(t, func() error {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		if got := sp.bytes; got != n {
+			return errors.Newf("expected %d bytes in flight, got %d", n, got)
+		}
+		return nil
+	})
+}
@@ -33,6 +33,9 @@ const (
 	OpAppend     OpCode = 0x01
 	OpAppendSync OpCode = 0x02 // Append + Sync in one round-trip (empty data = sync only)
 	OpRead       OpCode = 0x03
+	// OpAppendChain appends data and forwards it along a chain of replicas;
+	// the data is prefixed by a ChainHeader.
+	OpAppendChain OpCode = 0x04
 )
 
 // String returns the string representation of an OpCode.
@@ -44,6 +47,8 @@ func (op OpCode) String() string {
 		return "AppendSync"
 	case OpRead:
 		return "Read"
+	case OpAppendChain:
+		return "AppendChain"
 	default:
 		return "Unknown"
 	}
@@ -61,6 +66,10 @@ const (
 	StatusIOError       StatusCode = 0x04
 	StatusInvalidOp     StatusCode = 0x05
 	StatusBadRequest    StatusCode = 0x06
+	// StatusChainFailed reports that a downstream replica of an OpAppendChain
+	// request failed. The response data is a single byte holding the index in
+	// ChainHeader.Next of the failed replica.
+	StatusChainFailed StatusCode = 0x07
 )
 
 // String returns the string representation of a StatusCode.
@@ -80,6 +89,8 @@ func (s StatusCode) String() string {
 		return "InvalidOp"
 	case StatusBadRequest:
 		return "BadRequest"
+	case StatusChainFailed:
+		return "ChainFailed"
 	default:
 		return "Unknown"
 	}
@@ -102,6 +113,8 @@ func (s StatusCode) Error() error {
 		return ErrInvalidOp
 	case StatusBadRequest:
 		return ErrBadRequest
+	case StatusChainFailed:
+		return ErrChainFailed
 	default:
 		return errors.Newf("unknown status: %d", s)
 	}
@@ -115,6 +128,7 @@ var (
 	ErrIOError       = errors.New("I/O error")
 	ErrInvalidOp     = errors.New("invalid operation")
 	ErrBadRequest    = errors.New("bad request")
+	ErrChainFailed   = errors.New("chain replica failed")
 )
 
 // ObjectID is a 16-byte unique identifier for an object.
@@ -185,6 +199,88 @@ func ReadRequestHeader(r io.Reader) (RequestHeader, error) {
 	return DecodeRequestHeader(buf[:])
 }
 
+// MaxChainLength is the maximum number of downstream replicas in a
+// ChainHeader.
+const MaxChainLength = 255
+
+// ChainHeader prefixes the data of an OpAppendChain request. The receiving
+// server appends the data locally and then forwards it, with the remainder of
+// the chain, to Next[0]. It responds once every replica in the chain has
+// acknowledged, so a successful response from the head means the whole chain
+// holds the data.
+//
+// The request header's Length covers both the encoded ChainHeader and the
+// data.
+//
+// Wire format: Flags(1) + Count(1) + Count * (AddrLen(2) + Addr).
+type ChainHeader struct {
+	// Sync requests that every replica sync the data before acknowledging.
+	Sync bool
+	// Next holds the addresses of the downstream replicas, in chain order.
+	Next []string
+}
+
+// chainFlagSync is set in the flags byte of a ChainHeader when Sync is set.
+const chainFlagSync byte = 0x01
+
+// Size returns the encoded size of the chain header in bytes.
+func (h ChainHeader) Size() int {
+	n := 2
+	for _, addr := range h.Next {
+		n += 2 + len(addr)
+	}
+	return n
+}
+
+// AppendEncode appends the encoded chain header to buf and returns the
+// extended slice.
+func (h ChainHeader) AppendEncode(buf []byte) ([]byte, error) {
+	if len(h.Next) > MaxChainLength {
+		return buf, errors.Newf("chain too long: %d > %d", len(h.Next), MaxChainLength)
+	}
+	var flags byte
+	if h.Sync {
+		flags |= chainFlagSync
+	}
+	buf = append(buf, flags, byte(len(h.Next)))
+	for _, addr := range h.Next {
+		if len(addr) > 0xFFFF {
+			return buf, errors.Newf("chain address too long: %d bytes", len(addr))
+		}
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(addr)))
+		buf = append(buf, addr...)
+	}
+	return buf, nil
+}
+
+// DecodeChainHeader reads a chain header from the start of buf. It returns
+// the header and the number of bytes it occupied; the remainder of buf is the
+// data to append.
+func DecodeChainHeader(buf []byte) (ChainHeader, int, error) {
+	if len(buf) < 2 {
+		return ChainHeader{}, 0, errors.Newf("chain header too small: %d < 2", len(buf))
+	}
+	h := ChainHeader{Sync: buf[0]&chainFlagSync != 0}
+	count := int(buf[1])
+	n := 2
+	if count > 0 {
+		h.Next = make([]string, 0, count)
+	}
+	for i := 0; i < count; i++ {
+		if len(buf) < n+2 {
+			return ChainHeader{}, 0, errors.Newf("chain header truncated at replica %d", i)
+		}
+		l := int(binary.BigEndian.Uint16(buf[n:]))
+		n += 2
+		if len(buf) < n+l {
+			return ChainHeader{}, 0, errors.Newf("chain header truncated at replica %d", i)
+		}
+		h.Next = append(h.Next, string(buf[n:n+l]))
+		n += l
+	}
+	return h, n, nil
+}
+
 // ResponseHeader represents a response message header.
 type ResponseHeader struct {
 	Status StatusCode
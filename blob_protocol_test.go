@@ -145,6 +145,7 @@ func TestStatusCodeError(t *testing.T) {
 		{StatusIOError, ErrIOError},
 		{StatusBadRequest, ErrBadRequest},
 		{StatusInvalidOp, ErrInvalidOp},
+		{StatusChainFailed, ErrChainFailed},
 	}
 
 	for _, tt := range tests {
@@ -156,7 +157,7 @@ func TestStatusCodeError(t *testing.T) {
 }
 
 func TestAllOpCodes(t *testing.T) {
-	ops := []OpCode{OpAppend, OpAppendSync, OpRead}
+	ops := []OpCode{OpAppend, OpAppendSync, OpRead, OpAppendChain}
 	for _, op := range ops {
 		header := RequestHeader{
 			OpCode:   op,
@@ -183,6 +184,7 @@ func TestAllStatusCodes(t *testing.T) {
 	statuses := []StatusCode{
 		StatusOK, StatusNotFound, StatusAlreadyExists,
 		StatusSealed, StatusIOError, StatusInvalidOp, StatusBadRequest,
+		StatusChainFailed,
 	}
 	for _, status := range statuses {
 		header := ResponseHeader{
@@ -212,6 +214,7 @@ func TestOpCodeString(t *testing.T) {
 		{OpAppend, "Append"},
 		{OpAppendSync, "AppendSync"},
 		{OpRead, "Read"},
+		{OpAppendChain, "AppendChain"},
 		{OpCode(0xFF), "Unknown"},
 	}
 
@@ -235,6 +238,7 @@ func TestStatusCodeString(t *testing.T) {
 		{StatusIOError, "IOError"},
 		{StatusInvalidOp, "InvalidOp"},
 		{StatusBadRequest, "BadRequest"},
+		{StatusChainFailed, "ChainFailed"},
 		{StatusCode(0xFF), "Unknown"},
 	}
 
@@ -245,3 +249,55 @@ func TestStatusCodeString(t *testing.T) {
 		}
 	}
 }
+
+func TestChainHeaderEncodeDecode(t *testing.T) {
+	for _, original := range []ChainHeader{
+		{},
+		{Sync: true},
+		{Sync: true, Next: []string{"10.0.0.2:26258", "10.0.0.3:26258"}},
+		{Next: []string{""}},
+	} {
+		buf, err := original.AppendEncode(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(buf) != original.Size() {
+			t.Fatalf("encoded %d bytes, Size() = %d", len(buf), original.Size())
+		}
+		data := []byte("data")
+		decoded, n, err := DecodeChainHeader(append(buf, data...))
+		if err != nil {
+			t.Fatalf("DecodeChainHeader failed: %v", err)
+		}
+		if n != len(buf) {
+			t.Errorf("decoded %d bytes, want %d", n, len(buf))
+		}
+		if decoded.Sync != original.Sync || len(decoded.Next) != len(original.Next) {
+			t.Fatalf("got %+v, want %+v", decoded, original)
+		}
+		for i := range original.Next {
+			if decoded.Next[i] != original.Next[i] {
+				t.Errorf("Next[%d]: got %q, want %q", i, decoded.Next[i], original.Next[i])
+			}
+		}
+	}
+}
+
+func TestChainHeaderTruncated(t *testing.T) {
+	buf, err := ChainHeader{Next: []string{"a:1", "b:2"}}.AppendEncode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(buf); i++ {
+		if _, _, err := DecodeChainHeader(buf[:i]); err == nil {
+			t.Errorf("expected error decoding %d of %d bytes", i, len(buf))
+		}
+	}
+}
+
+func TestChainHeaderTooLong(t *testing.T) {
+	h := ChainHeader{Next: make([]string, MaxChainLength+1)}
+	if _, err := h.AppendEncode(nil); err == nil {
+		t.Fatal("expected error for chain longer than MaxChainLength")
+	}
+}
@@ -3,28 +3,108 @@ package basaltclient
 import (
 	"context"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ControllerClientConfig configures a ControllerClient.
 type ControllerClientConfig struct {
 	// Logger is the logger for diagnostic messages. If nil, DefaultLogger is used.
 	Logger Logger
+	// Retry configures the retrying of idempotent RPCs. The zero value uses
+	// the defaults described on RetryPolicy.
+	Retry RetryPolicy
+}
+
+// RetryPolicy configures how ControllerClient retries idempotent RPCs that
+// fail with a retryable error (see IsRetryable). Retries that fail over to a
+// controller not yet tried are made immediately; other retries wait for an
+// exponentially increasing backoff first.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of an RPC, including the
+	// first. Every controller is tried at least once regardless, so a
+	// MaxAttempts of 1 only fails over. 0 uses the default of 5.
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry that waits. 0
+	// uses the default of 50ms.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum backoff between retries. 0 uses the default
+	// of 2s.
+	MaxBackoff time.Duration
+}
+
+const (
+	defaultRetryMaxAttempts    = 5
+	defaultRetryInitialBackoff = 50 * time.Millisecond
+	defaultRetryMaxBackoff     = 2 * time.Second
+)
+
+// withDefaults returns the policy with defaults filled in.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultRetryMaxBackoff
+	}
+	return p
 }
 
 // ControllerClient is the controller gRPC client.
+//
+// A ControllerClient may be given the addresses of several controllers, such
+// as the Controllers of a ParsedPath. It sends every RPC to the controller
+// that last served a request successfully, and fails over to the next
+// controller when that one becomes unavailable, i.e. an RPC fails with
+// codes.Unavailable, as it does when the controller is down or is not the
+// current leader.
+//
+// RPCs that are safe to repeat are retried according to the client's
+// RetryPolicy, failing over as they go. These are stats, listings, seals and
+// failure reports, which are naturally idempotent, and mounts, creates, links
+// and renames, which carry a request ID that lets the controller recognize a
+// retry of a request it has already applied. Other RPCs return the first
+// error, and the next call is sent to the next controller.
+//
+// A successful Mount makes its mount the client's active mount, until it is
+// unmounted with Unmount or replaced by another Mount. Mutating RPCs carry a
+// fence identifying the active mount, which the controller uses to reject
+// them with ErrFenced once the mount has been taken over by another
+// instance. A client should therefore hold at most one mount at a time; use
+// a ControllerClient per mount to hold several.
+//
+// ControllerClient is safe for concurrent use.
 type ControllerClient struct {
-	addr   string
-	logger Logger
-	conn   *grpc.ClientConn
-	client basaltpb.ControllerClient
+	addrs   []string
+	logger  Logger
+	retry   RetryPolicy
+	conns   []*grpc.ClientConn
+	clients []basaltpb.ControllerClient
+
+	mu      sync.Mutex
+	current int                  // index of the controller RPCs are sent to
+	fence   *basaltpb.MountFence // fence of the active mount, if any
+}
+
+// objectSealer is the subset of ControllerClient used by QuorumWriter.Seal.
+// This interface exists primarily for testing; production code uses
+// *ControllerClient.
+type objectSealer interface {
+	Seal(ctx context.Context, objectID []byte, size int64) error
 }
 
-// NewControllerClient creates a new controller client connected to addr.
-func NewControllerClient(addr string, cfg ...ControllerClientConfig) (*ControllerClient, error) {
+// NewControllerClient creates a new controller client for the controllers
+// at addrs, starting with the first. Connections are established lazily.
+func NewControllerClient(addrs []string, cfg ...ControllerClientConfig) (*ControllerClient, error) {
 	var c ControllerClientConfig
 	if len(cfg) > 0 {
 		c = cfg[0]
@@ -32,29 +112,123 @@ func NewControllerClient(addr string, cfg ...ControllerClientConfig) (*Controlle
 	if c.Logger == nil {
 		c.Logger = DefaultLogger
 	}
-
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, err
+	if len(addrs) == 0 {
+		return nil, errors.New("no controller addresses")
 	}
-	return &ControllerClient{
-		addr:   addr,
+
+	client := &ControllerClient{
+		addrs:  append([]string(nil), addrs...),
 		logger: c.Logger,
-		conn:   conn,
-		client: basaltpb.NewControllerClient(conn),
-	}, nil
+		retry:  c.Retry.withDefaults(),
+	}
+	for _, addr := range addrs {
+		conn, err := dialGRPC(addr)
+		if err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+		client.conns = append(client.conns, conn)
+		client.clients = append(client.clients, basaltpb.NewControllerClient(conn))
+	}
+	return client, nil
 }
 
-// Close closes the client connection.
+// Close closes the client connections.
 func (c *ControllerClient) Close() error {
-	return c.conn.Close()
+	var err error
+	for _, conn := range c.conns {
+		err = errors.CombineErrors(err, conn.Close())
+	}
+	return err
+}
+
+// Addr returns the address of the controller that RPCs are currently sent
+// to.
+func (c *ControllerClient) Addr() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.addrs[c.current]
+}
+
+// invoke runs fn against the current controller, failing over to the next
+// controller if it is unavailable. If idempotent is set, fn is retried
+// according to the retry policy while it fails with a retryable error; fn
+// must then be safe to run more than once.
+func (c *ControllerClient) invoke(
+	ctx context.Context, idempotent bool, fn func(basaltpb.ControllerClient) error,
+) error {
+	maxAttempts := max(c.retry.MaxAttempts, len(c.clients))
+	var backoff time.Duration
+	sinceBackoff := 0 // attempts since the last backoff
+	for attempt := 1; ; attempt++ {
+		c.mu.Lock()
+		i := c.current
+		c.mu.Unlock()
+
+		err := fn(c.clients[i])
+		if !IsRetryable(err) {
+			return err
+		}
+		failedOver := status.Code(err) == codes.Unavailable && len(c.clients) > 1
+		if failedOver {
+			c.failover(i, err)
+		}
+		if !idempotent || attempt >= maxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		// Fail over to controllers not yet tried immediately, and back off
+		// before trying a controller again.
+		sinceBackoff++
+		if !failedOver || sinceBackoff >= len(c.clients) {
+			backoff = min(max(2*backoff, c.retry.InitialBackoff), c.retry.MaxBackoff)
+			if !sleepCtx(ctx, backoff) {
+				return err
+			}
+			sinceBackoff = 0
+		}
+	}
+}
+
+// sleepCtx waits for d to elapse. Returns false if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// failover moves on from controller i, which failed with err, to the next
+// controller, unless another RPC has already done so.
+func (c *ControllerClient) failover(i int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.current != i {
+		return
+	}
+	c.current = (i + 1) % len(c.clients)
+	c.logger.Infof("basaltclient: controller %s unavailable, failing over to %s: %v",
+		c.addrs[i], c.addrs[c.current], err)
+}
+
+// mountFence returns the fence of the active mount, or nil if there is none.
+func (c *ControllerClient) mountFence() *basaltpb.MountFence {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fence
 }
 
 // Mount registers a Pebble instance and acquires exclusive write access to its store directory.
+// The mount becomes the client's active mount. It returns an error marked with ErrMountConflict
+// if the store is mounted by another instance; see TakeoverMount.
 func (c *ControllerClient) Mount(
 	ctx context.Context, instanceID string, zone string, clusterID []byte, storeID []byte,
 ) (*basaltpb.MountResponse, error) {
-	return c.client.Mount(ctx, &basaltpb.MountRequest{
+	return c.mount(ctx, &basaltpb.MountRequest{
 		InstanceId: instanceID,
 		Zone:       zone,
 		ClusterId:  basaltpb.UUIDFromBytes(clusterID),
@@ -62,21 +236,102 @@ func (c *ControllerClient) Mount(
 	})
 }
 
-// Unmount releases the write lock on a store directory.
+// TakeoverMount is like Mount, but if the store is mounted by another
+// instance, it revokes that mount and mounts the store in its place. This is
+// for failing over a store from an instance that is unresponsive; the new
+// mount's epoch fences the revoked mount, so that the previous instance can
+// no longer mutate the store's namespace.
+//
+// The response identifies the revoked mount, if any, in PreviousMount, and
+// lists the unsealed objects in the store in OpenObjects. The revoked mount
+// may have been writing these, so the caller should recover them, e.g. with
+// RecoverQuorumPrefix, before relying on their contents.
+func (c *ControllerClient) TakeoverMount(
+	ctx context.Context, instanceID string, zone string, clusterID []byte, storeID []byte,
+) (*basaltpb.MountResponse, error) {
+	return c.mount(ctx, &basaltpb.MountRequest{
+		InstanceId: instanceID,
+		Zone:       zone,
+		ClusterId:  basaltpb.UUIDFromBytes(clusterID),
+		StoreId:    basaltpb.UUIDFromBytes(storeID),
+		Takeover:   true,
+	})
+}
+
+// mount sends req, making its mount the active mount.
+func (c *ControllerClient) mount(
+	ctx context.Context, req *basaltpb.MountRequest,
+) (*basaltpb.MountResponse, error) {
+	req.RequestId = basaltpb.NewUUID()
+	var resp *basaltpb.MountResponse
+	err := c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		var err error
+		resp, err = client.Mount(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.fence = &basaltpb.MountFence{MountId: resp.MountId, Epoch: resp.Epoch}
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// Unmount releases the write lock on a store directory. If the mount is the
+// client's active mount, the client no longer has one.
 func (c *ControllerClient) Unmount(ctx context.Context, mountID []byte) error {
-	_, err := c.client.Unmount(ctx, &basaltpb.UnmountRequest{
-		MountId: basaltpb.UUIDFromBytes(mountID),
+	id := basaltpb.UUIDFromBytes(mountID)
+	err := c.invoke(ctx, false /* idempotent */, func(client basaltpb.ControllerClient) error {
+		_, err := client.Unmount(ctx, &basaltpb.UnmountRequest{MountId: id})
+		return err
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.fence != nil && c.fence.MountId == id {
+		c.fence = nil
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// HeartbeatMount renews the lease of a mount, returning the duration of the
+// renewed lease. It returns an error marked with ErrNotMounted if the mount
+// has expired or been unmounted. Most callers should use a MountSession,
+// which heartbeats in the background, instead.
+func (c *ControllerClient) HeartbeatMount(
+	ctx context.Context, mountID []byte,
+) (time.Duration, error) {
+	var resp *basaltpb.HeartbeatMountResponse
+	err := c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		var err error
+		resp, err = client.HeartbeatMount(ctx, &basaltpb.HeartbeatMountRequest{
+			MountId: basaltpb.UUIDFromBytes(mountID),
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(resp.LeaseDurationNanos), nil
 }
 
 // Mkdir creates a subdirectory within a directory.
 func (c *ControllerClient) Mkdir(
 	ctx context.Context, parentID []byte, name string,
 ) (basaltpb.UUID, error) {
-	resp, err := c.client.Mkdir(ctx, &basaltpb.MkdirRequest{
-		ParentId: basaltpb.UUIDFromBytes(parentID),
-		Name:     name,
+	fence := c.mountFence()
+	var resp *basaltpb.MkdirResponse
+	err := c.invoke(ctx, false /* idempotent */, func(client basaltpb.ControllerClient) error {
+		var err error
+		resp, err = client.Mkdir(ctx, &basaltpb.MkdirRequest{
+			ParentId: basaltpb.UUIDFromBytes(parentID),
+			Name:     name,
+			Fence:    fence,
+		})
+		return err
 	})
 	if err != nil {
 		return basaltpb.UUID{}, err
@@ -86,21 +341,34 @@ func (c *ControllerClient) Mkdir(
 
 // Rmdir removes an empty directory.
 func (c *ControllerClient) Rmdir(ctx context.Context, parentID []byte, name string) error {
-	_, err := c.client.Rmdir(ctx, &basaltpb.RmdirRequest{
-		ParentId: basaltpb.UUIDFromBytes(parentID),
-		Name:     name,
+	fence := c.mountFence()
+	return c.invoke(ctx, false /* idempotent */, func(client basaltpb.ControllerClient) error {
+		_, err := client.Rmdir(ctx, &basaltpb.RmdirRequest{
+			ParentId: basaltpb.UUIDFromBytes(parentID),
+			Name:     name,
+			Fence:    fence,
+		})
+		return err
 	})
-	return err
 }
 
 // Create allocates a new file in a directory and selects replicas.
 func (c *ControllerClient) Create(
 	ctx context.Context, directoryID []byte, name string, policy *basaltpb.ReplicationPolicy,
 ) (*basaltpb.ObjectMeta, error) {
-	resp, err := c.client.Create(ctx, &basaltpb.CreateRequest{
-		DirectoryId: basaltpb.UUIDFromBytes(directoryID),
-		Name:        name,
-		Policy:      policy,
+	requestID := basaltpb.NewUUID()
+	fence := c.mountFence()
+	var resp *basaltpb.CreateResponse
+	err := c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		var err error
+		resp, err = client.Create(ctx, &basaltpb.CreateRequest{
+			DirectoryId: basaltpb.UUIDFromBytes(directoryID),
+			Name:        name,
+			Policy:      policy,
+			RequestId:   requestID,
+			Fence:       fence,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -115,11 +383,17 @@ func (c *ControllerClient) Create(
 func (c *ControllerClient) StatByID(
 	ctx context.Context, objectID []byte, includeReferences bool, includeZombies bool,
 ) (*basaltpb.StatResponse, error) {
-	return c.client.StatByID(ctx, &basaltpb.StatByIDRequest{
-		ObjectId:          basaltpb.UUIDFromBytes(objectID),
-		IncludeReferences: includeReferences,
-		IncludeZombies:    includeZombies,
+	var resp *basaltpb.StatResponse
+	err := c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		var err error
+		resp, err = client.StatByID(ctx, &basaltpb.StatByIDRequest{
+			ObjectId:          basaltpb.UUIDFromBytes(objectID),
+			IncludeReferences: includeReferences,
+			IncludeZombies:    includeZombies,
+		})
+		return err
 	})
+	return resp, err
 }
 
 // StatByPath returns metadata for an object by (directory_id, name).
@@ -127,77 +401,134 @@ func (c *ControllerClient) StatByID(
 func (c *ControllerClient) StatByPath(
 	ctx context.Context, directoryID []byte, name string, includeReferences bool,
 ) (*basaltpb.StatResponse, error) {
-	return c.client.StatByPath(ctx, &basaltpb.StatByPathRequest{
-		DirectoryId:       basaltpb.UUIDFromBytes(directoryID),
-		Name:              name,
-		IncludeReferences: includeReferences,
+	var resp *basaltpb.StatResponse
+	err := c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		var err error
+		resp, err = client.StatByPath(ctx, &basaltpb.StatByPathRequest{
+			DirectoryId:       basaltpb.UUIDFromBytes(directoryID),
+			Name:              name,
+			IncludeReferences: includeReferences,
+		})
+		return err
 	})
+	return resp, err
 }
 
 // Unlink removes an entry from a directory.
 func (c *ControllerClient) Unlink(
 	ctx context.Context, directoryID []byte, name string,
 ) (*basaltpb.UnlinkResponse, error) {
-	return c.client.Unlink(ctx, &basaltpb.UnlinkRequest{
-		DirectoryId: basaltpb.UUIDFromBytes(directoryID),
-		Name:        name,
+	fence := c.mountFence()
+	var resp *basaltpb.UnlinkResponse
+	err := c.invoke(ctx, false /* idempotent */, func(client basaltpb.ControllerClient) error {
+		var err error
+		resp, err = client.Unlink(ctx, &basaltpb.UnlinkRequest{
+			DirectoryId: basaltpb.UUIDFromBytes(directoryID),
+			Name:        name,
+			Fence:       fence,
+		})
+		return err
 	})
+	return resp, err
 }
 
-// Seal marks an object as immutable with its final size.
+// Seal marks an object as immutable with its final size. Sealing an object
+// again with the same size has no further effect, so Seal is retried on
+// failover.
 func (c *ControllerClient) Seal(ctx context.Context, objectID []byte, size int64) error {
-	_, err := c.client.Seal(ctx, &basaltpb.SealRequest{
-		ObjectId: basaltpb.UUIDFromBytes(objectID),
-		Size_:    size,
+	fence := c.mountFence()
+	return c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		_, err := client.Seal(ctx, &basaltpb.SealRequest{
+			ObjectId: basaltpb.UUIDFromBytes(objectID),
+			Size_:    size,
+			Fence:    fence,
+		})
+		return err
 	})
-	return err
 }
 
 // Link creates a hardlink to an existing object in a directory.
 func (c *ControllerClient) Link(
 	ctx context.Context, directoryID []byte, name string, objectID []byte,
 ) error {
-	_, err := c.client.Link(ctx, &basaltpb.LinkRequest{
-		DirectoryId: basaltpb.UUIDFromBytes(directoryID),
-		Name:        name,
-		ObjectId:    basaltpb.UUIDFromBytes(objectID),
+	requestID := basaltpb.NewUUID()
+	fence := c.mountFence()
+	return c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		_, err := client.Link(ctx, &basaltpb.LinkRequest{
+			DirectoryId: basaltpb.UUIDFromBytes(directoryID),
+			Name:        name,
+			ObjectId:    basaltpb.UUIDFromBytes(objectID),
+			RequestId:   requestID,
+			Fence:       fence,
+		})
+		return err
 	})
-	return err
 }
 
 // Rename moves an entry within the same directory.
 func (c *ControllerClient) Rename(
 	ctx context.Context, directoryID []byte, oldName string, newName string,
 ) error {
-	_, err := c.client.Rename(ctx, &basaltpb.RenameRequest{
-		DirectoryId: basaltpb.UUIDFromBytes(directoryID),
-		OldName:     oldName,
-		NewName:     newName,
+	requestID := basaltpb.NewUUID()
+	fence := c.mountFence()
+	return c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		_, err := client.Rename(ctx, &basaltpb.RenameRequest{
+			DirectoryId: basaltpb.UUIDFromBytes(directoryID),
+			OldName:     oldName,
+			NewName:     newName,
+			RequestId:   requestID,
+			Fence:       fence,
+		})
+		return err
 	})
-	return err
 }
 
 // List returns all entries in a directory.
 func (c *ControllerClient) List(
 	ctx context.Context, directoryID []byte,
 ) ([]basaltpb.DirectoryEntry, error) {
-	stream, err := c.client.List(ctx, &basaltpb.ListRequest{
-		DirectoryId: basaltpb.UUIDFromBytes(directoryID),
-	})
-	if err != nil {
-		return nil, err
-	}
-
 	var entries []basaltpb.DirectoryEntry
-	for {
-		entry, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
+	err := c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		entries = nil
+		stream, err := client.List(ctx, &basaltpb.ListRequest{
+			DirectoryId: basaltpb.UUIDFromBytes(directoryID),
+		})
 		if err != nil {
-			return nil, err
+			return err
+		}
+		for {
+			entry, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			entries = append(entries, *entry)
 		}
-		entries = append(entries, *entry)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return entries, nil
 }
+
+// ReportFailure reports that a replica of an object, held by the blob server
+// at addr, is persistently failing.
+func (c *ControllerClient) ReportFailure(
+	ctx context.Context,
+	objectID []byte,
+	addr string,
+	kind basaltpb.ReplicaFailureKind,
+	detail string,
+) error {
+	return c.invoke(ctx, true /* idempotent */, func(client basaltpb.ControllerClient) error {
+		_, err := client.ReportFailure(ctx, &basaltpb.ReportFailureRequest{
+			ObjectId: basaltpb.UUIDFromBytes(objectID),
+			Addr:     addr,
+			Kind:     kind,
+			Detail:   detail,
+		})
+		return err
+	})
+}
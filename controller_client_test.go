@@ -0,0 +1,564 @@
+package basaltclient
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testController is a controller that serves StatByID, Mkdir, Create, List
+// and mounts, or fails every RPC with codes.Unavailable while it is not the
+// leader.
+type testController struct {
+	basaltpb.UnimplementedControllerServer
+	addr string
+
+	mu         sync.Mutex
+	leader     bool
+	requests   int
+	failNext   []error                // errors to fail the next requests with
+	requestIDs []basaltpb.UUID        // request IDs of Create requests
+	fences     []*basaltpb.MountFence // fences of Create requests
+	lease      time.Duration          // lease duration granted to mounts
+	mounts     map[basaltpb.UUID]int  // heartbeats received by each mount
+	epoch      uint64                 // epoch of the latest mount
+	active     *basaltpb.MountFence   // fence of the current mount, if any
+	open       []*basaltpb.ObjectMeta // unsealed objects reported on takeover
+}
+
+func newTestController(t *testing.T, leader bool) *testController {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &testController{
+		addr:   ln.Addr().String(),
+		leader: leader,
+		mounts: make(map[basaltpb.UUID]int),
+	}
+	s := grpc.NewServer()
+	basaltpb.RegisterControllerServer(s, c)
+	go func() { _ = s.Serve(ln) }()
+	t.Cleanup(s.Stop)
+	return c
+}
+
+func (c *testController) setLeader(leader bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.leader = leader
+}
+
+func (c *testController) serve() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	if !c.leader {
+		return status.Error(codes.Unavailable, "not the leader")
+	}
+	if len(c.failNext) > 0 {
+		err := c.failNext[0]
+		c.failNext = c.failNext[1:]
+		return err
+	}
+	return nil
+}
+
+// failWith fails the next requests with errs.
+func (c *testController) failWith(errs ...error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failNext = errs
+}
+
+func (c *testController) numRequests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+func (c *testController) StatByID(
+	ctx context.Context, req *basaltpb.StatByIDRequest,
+) (*basaltpb.StatResponse, error) {
+	if err := c.serve(); err != nil {
+		return nil, err
+	}
+	return &basaltpb.StatResponse{}, nil
+}
+
+func (c *testController) Mkdir(
+	ctx context.Context, req *basaltpb.MkdirRequest,
+) (*basaltpb.MkdirResponse, error) {
+	if err := c.serve(); err != nil {
+		return nil, err
+	}
+	return &basaltpb.MkdirResponse{}, nil
+}
+
+func (c *testController) createRequestIDs() []basaltpb.UUID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]basaltpb.UUID(nil), c.requestIDs...)
+}
+
+func (c *testController) createFences() []*basaltpb.MountFence {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*basaltpb.MountFence(nil), c.fences...)
+}
+
+func (c *testController) Create(
+	ctx context.Context, req *basaltpb.CreateRequest,
+) (*basaltpb.CreateResponse, error) {
+	c.mu.Lock()
+	c.requestIDs = append(c.requestIDs, req.RequestId)
+	c.fences = append(c.fences, req.Fence)
+	stale := req.Fence != nil && req.Fence.Epoch < c.epoch
+	c.mu.Unlock()
+	if err := c.serve(); err != nil {
+		return nil, err
+	}
+	if stale {
+		return nil, errWithReason(codes.FailedPrecondition, "FENCED")
+	}
+	return &basaltpb.CreateResponse{Meta: &basaltpb.ObjectMeta{}}, nil
+}
+
+func (c *testController) List(
+	req *basaltpb.ListRequest, stream basaltpb.Controller_ListServer,
+) error {
+	if err := c.serve(); err != nil {
+		return err
+	}
+	return stream.Send(&basaltpb.DirectoryEntry{Name: "entry"})
+}
+
+func (c *testController) Mount(
+	ctx context.Context, req *basaltpb.MountRequest,
+) (*basaltpb.MountResponse, error) {
+	if err := c.serve(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &basaltpb.MountResponse{
+		MountId:            basaltpb.NewUUID(),
+		DirectoryId:        basaltpb.NewUUID(),
+		LeaseDurationNanos: int64(c.lease),
+	}
+	if c.active != nil {
+		if !req.Takeover {
+			return nil, errWithReason(codes.FailedPrecondition, "MOUNT_CONFLICT")
+		}
+		delete(c.mounts, c.active.MountId)
+		resp.PreviousMount = c.active
+		resp.OpenObjects = c.open
+	}
+	c.epoch++
+	resp.Epoch = c.epoch
+	c.mounts[resp.MountId] = 0
+	c.active = &basaltpb.MountFence{MountId: resp.MountId, Epoch: resp.Epoch}
+	return resp, nil
+}
+
+func (c *testController) Unmount(
+	ctx context.Context, req *basaltpb.UnmountRequest,
+) (*basaltpb.UnmountResponse, error) {
+	if err := c.serve(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.mounts[req.MountId]; !ok {
+		return nil, errWithReason(codes.FailedPrecondition, "NOT_MOUNTED")
+	}
+	delete(c.mounts, req.MountId)
+	c.active = nil
+	return &basaltpb.UnmountResponse{}, nil
+}
+
+func (c *testController) HeartbeatMount(
+	ctx context.Context, req *basaltpb.HeartbeatMountRequest,
+) (*basaltpb.HeartbeatMountResponse, error) {
+	if err := c.serve(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.mounts[req.MountId]; !ok {
+		return nil, errWithReason(codes.FailedPrecondition, "NOT_MOUNTED")
+	}
+	c.mounts[req.MountId]++
+	return &basaltpb.HeartbeatMountResponse{LeaseDurationNanos: int64(c.lease)}, nil
+}
+
+// setOpenObjects sets the unsealed objects reported by a takeover.
+func (c *testController) setOpenObjects(open []*basaltpb.ObjectMeta) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.open = open
+}
+
+func (c *testController) setLease(lease time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lease = lease
+}
+
+// expire expires the mount, as the controller does when its lease lapses.
+func (c *testController) expire(mountID basaltpb.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.mounts, mountID)
+	if c.active != nil && c.active.MountId == mountID {
+		c.active = nil
+	}
+}
+
+// heartbeats returns the number of heartbeats received by the mount, and
+// whether it is still mounted.
+func (c *testController) heartbeats(mountID basaltpb.UUID) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, ok := c.mounts[mountID]
+	return n, ok
+}
+
+// errWithReason returns an error with the code and an errdetails.ErrorInfo
+// with the reason, as the controller returns.
+func errWithReason(code codes.Code, reason string) error {
+	s, err := status.New(code, reason).WithDetails(&errdetails.ErrorInfo{Reason: reason})
+	if err != nil {
+		panic(err)
+	}
+	return s.Err()
+}
+
+func TestControllerClientFailover(t *testing.T) {
+	// The first address has no controller listening.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := ln.Addr().String()
+	_ = ln.Close()
+
+	follower := newTestController(t, false)
+	leader := newTestController(t, true)
+	c, err := NewControllerClient([]string{down, follower.addr, leader.addr},
+		ControllerClientConfig{Retry: RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+
+	// An idempotent RPC is retried until it reaches the leader, which
+	// subsequent RPCs are sent to directly.
+	if _, err := c.StatByID(ctx, make([]byte, 16), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if c.Addr() != leader.addr {
+		t.Fatalf("expected to fail over to %s, got %s", leader.addr, c.Addr())
+	}
+	if _, err := c.Mkdir(ctx, make([]byte, 16), "dir"); err != nil {
+		t.Fatal(err)
+	}
+	if n := follower.numRequests(); n != 1 {
+		t.Fatalf("expected 1 request to the follower, got %d", n)
+	}
+
+	// A non-idempotent RPC fails when the leader steps down, but moves the
+	// client on so that the next attempt can succeed.
+	leader.setLeader(false)
+	follower.setLeader(true)
+	_, err = c.Mkdir(ctx, make([]byte, 16), "dir")
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if c.Addr() != down {
+		t.Fatalf("expected to fail over to %s, got %s", down, c.Addr())
+	}
+	if _, err := c.StatByID(ctx, make([]byte, 16), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if c.Addr() != follower.addr {
+		t.Fatalf("expected to fail over to %s, got %s", follower.addr, c.Addr())
+	}
+
+	// Once no controller is available, idempotent RPCs fail after trying each
+	// once.
+	follower.setLeader(false)
+	before := follower.numRequests() + leader.numRequests()
+	if _, err := c.StatByID(ctx, make([]byte, 16), false, false); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if n := follower.numRequests() + leader.numRequests() - before; n != 2 {
+		t.Fatalf("expected each controller to be tried once, got %d requests", n)
+	}
+}
+
+func TestControllerClientNoAddrs(t *testing.T) {
+	if _, err := NewControllerClient(nil); err == nil {
+		t.Fatal("expected error without addresses")
+	}
+}
+
+func TestControllerClientRetry(t *testing.T) {
+	ctrl := newTestController(t, true)
+	c, err := NewControllerClient([]string{ctrl.addr}, ControllerClientConfig{
+		Retry: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	busy := status.Error(codes.ResourceExhausted, "busy")
+
+	// Create is retried with the same request ID.
+	ctrl.failWith(unavailable, busy)
+	if _, err := c.Create(ctx, make([]byte, 16), "file", nil); err != nil {
+		t.Fatal(err)
+	}
+	ids := ctrl.createRequestIDs()
+	if len(ids) != 3 || ids[0].IsZero() || ids[1] != ids[0] || ids[2] != ids[0] {
+		t.Fatalf("expected 3 attempts with the same request ID, got %v", ids)
+	}
+
+	// Retries stop after MaxAttempts.
+	ctrl.failWith(busy, busy, busy, busy)
+	if _, err := c.Create(ctx, make([]byte, 16), "file", nil); !IsRetryable(err) {
+		t.Fatalf("expected retryable error, got %v", err)
+	}
+	ids = ctrl.createRequestIDs()
+	if len(ids) != 6 {
+		t.Fatalf("expected 3 more attempts, got %d", len(ids)-3)
+	}
+	if ids[3] == ids[0] {
+		t.Fatal("expected a new request ID for a new call")
+	}
+	ctrl.failWith()
+
+	// Mkdir is not idempotent, so it is not retried.
+	before := ctrl.numRequests()
+	ctrl.failWith(unavailable)
+	if _, err := c.Mkdir(ctx, make([]byte, 16), "dir"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if n := ctrl.numRequests() - before; n != 1 {
+		t.Fatalf("expected 1 attempt, got %d", n)
+	}
+
+	// Errors that are not retryable are returned immediately.
+	before = ctrl.numRequests()
+	ctrl.failWith(status.Error(codes.NotFound, "no such object"))
+	_, err = c.StatByID(ctx, make([]byte, 16), false, false)
+	if !IsNotFound(err) || IsRetryable(err) {
+		t.Fatalf("expected a NotFound error, got %v", err)
+	}
+	if n := ctrl.numRequests() - before; n != 1 {
+		t.Fatalf("expected 1 attempt, got %d", n)
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+		notFound  bool
+	}{
+		{nil, false, false},
+		{ErrNotFound, false, true},
+		{errors.Wrap(ErrNotFound, "reading"), false, true},
+		{errors.Mark(errors.New("connection reset"), errConnFailed), true, false},
+		{ErrBadRequest, false, false},
+		{status.Error(codes.NotFound, "not found"), false, true},
+		{status.Error(codes.Unavailable, "unavailable"), true, false},
+		{errors.Wrap(status.Error(codes.Aborted, "aborted"), "creating"), true, false},
+		{status.Error(codes.InvalidArgument, "invalid"), false, false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.retryable {
+			t.Errorf("IsRetryable(%v) = %t, want %t", tt.err, got, tt.retryable)
+		}
+		if got := IsNotFound(tt.err); got != tt.notFound {
+			t.Errorf("IsNotFound(%v) = %t, want %t", tt.err, got, tt.notFound)
+		}
+	}
+}
+
+func TestControllerClientErrorTranslation(t *testing.T) {
+	ctrl := newTestController(t, true)
+	c, err := NewControllerClient([]string{ctrl.addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+
+	tests := []struct {
+		err  error
+		want error
+		code codes.Code
+	}{
+		{status.Error(codes.NotFound, "no such directory"), ErrNotFound, codes.NotFound},
+		{status.Error(codes.AlreadyExists, "exists"), ErrAlreadyExists, codes.AlreadyExists},
+		{errWithReason(codes.FailedPrecondition, "NOT_MOUNTED"), ErrNotMounted, codes.FailedPrecondition},
+		{errWithReason(codes.FailedPrecondition, "DIRECTORY_NOT_EMPTY"), ErrDirectoryNotEmpty, codes.FailedPrecondition},
+		{errWithReason(codes.FailedPrecondition, "MOUNT_CONFLICT"), ErrMountConflict, codes.FailedPrecondition},
+		{errWithReason(codes.FailedPrecondition, "SEALED"), ErrSealed, codes.FailedPrecondition},
+		{errWithReason(codes.FailedPrecondition, "FENCED"), ErrFenced, codes.FailedPrecondition},
+		{errWithReason(codes.NotFound, "UNKNOWN_REASON"), ErrNotFound, codes.NotFound},
+		{status.Error(codes.FailedPrecondition, "precondition"), nil, codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			// Check both unary and streaming RPCs.
+			ctrl.failWith(tt.err)
+			_, unaryErr := c.Mkdir(ctx, make([]byte, 16), "dir")
+			ctrl.failWith(tt.err)
+			_, streamErr := c.List(ctx, make([]byte, 16))
+			for _, err := range []error{unaryErr, streamErr} {
+				if tt.want != nil && !errors.Is(err, tt.want) {
+					t.Errorf("expected %v, got %v", tt.want, err)
+				}
+				if tt.want == nil && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrNotMounted)) {
+					t.Errorf("expected untranslated error, got %v", err)
+				}
+				if code := status.Code(err); code != tt.code {
+					t.Errorf("expected code %s, got %s", tt.code, code)
+				}
+			}
+		})
+	}
+
+	entries, err := c.List(ctx, make([]byte, 16))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one entry, got %v, %v", entries, err)
+	}
+}
+
+func TestControllerClientMountFence(t *testing.T) {
+	ctrl := newTestController(t, true)
+	newClient := func() *ControllerClient {
+		c, err := NewControllerClient([]string{ctrl.addr})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = c.Close() })
+		return c
+	}
+	ctx := context.Background()
+	create := func(c *ControllerClient) (*basaltpb.MountFence, error) {
+		_, err := c.Create(ctx, make([]byte, 16), "file", nil)
+		fences := ctrl.createFences()
+		return fences[len(fences)-1], err
+	}
+
+	// Without a mount, requests carry no fence.
+	c1 := newClient()
+	if fence, err := create(c1); err != nil || fence != nil {
+		t.Fatalf("expected no fence, got %v, %v", fence, err)
+	}
+
+	// Requests carry the fence of the active mount.
+	resp1, err := c1.Mount(ctx, "instance1", "zone", make([]byte, 16), make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fence, err := create(c1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := basaltpb.MountFence{MountId: resp1.MountId, Epoch: resp1.Epoch}
+	if fence == nil || *fence != want {
+		t.Fatalf("expected fence %v, got %v", want, fence)
+	}
+
+	// Once another instance takes over the mount, the first is fenced.
+	c2 := newClient()
+	resp2, err := c2.TakeoverMount(ctx, "instance2", "zone", make([]byte, 16), make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp2.Epoch <= resp1.Epoch {
+		t.Fatalf("expected epoch to increase: %d <= %d", resp2.Epoch, resp1.Epoch)
+	}
+	if _, err := create(c1); !errors.Is(err, ErrFenced) {
+		t.Fatalf("expected ErrFenced, got %v", err)
+	}
+	if fence, err := create(c2); err != nil || fence.Epoch != resp2.Epoch {
+		t.Fatalf("expected fence with epoch %d, got %v, %v", resp2.Epoch, fence, err)
+	}
+
+	// Unmounting clears the active mount.
+	if err := c2.Unmount(ctx, resp2.MountId[:]); err != nil {
+		t.Fatal(err)
+	}
+	if fence, err := create(c2); err != nil || fence != nil {
+		t.Fatalf("expected no fence, got %v, %v", fence, err)
+	}
+}
+
+func TestControllerClientTakeoverMount(t *testing.T) {
+	ctrl := newTestController(t, true)
+	open := []*basaltpb.ObjectMeta{{Id: basaltpb.NewUUID()}, {Id: basaltpb.NewUUID()}}
+	ctrl.setOpenObjects(open)
+	c, err := NewControllerClient([]string{ctrl.addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+	clusterID, storeID := make([]byte, 16), make([]byte, 16)
+
+	// An unmounted store is taken over without revoking anything.
+	resp1, err := c.TakeoverMount(ctx, "instance1", "zone", clusterID, storeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp1.PreviousMount != nil || len(resp1.OpenObjects) != 0 {
+		t.Fatalf("expected no previous mount, got %v, %v", resp1.PreviousMount, resp1.OpenObjects)
+	}
+
+	// Mounting a mounted store conflicts.
+	if _, err := c.Mount(ctx, "instance2", "zone", clusterID, storeID); !errors.Is(err, ErrMountConflict) {
+		t.Fatalf("expected ErrMountConflict, got %v", err)
+	}
+
+	// Taking it over revokes the previous mount and reports its open objects.
+	resp2, err := c.TakeoverMount(ctx, "instance2", "zone", clusterID, storeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := basaltpb.MountFence{MountId: resp1.MountId, Epoch: resp1.Epoch}
+	if resp2.PreviousMount == nil || *resp2.PreviousMount != want {
+		t.Fatalf("expected previous mount %v, got %v", want, resp2.PreviousMount)
+	}
+	if resp2.Epoch <= resp1.Epoch {
+		t.Fatalf("expected epoch to increase: %d <= %d", resp2.Epoch, resp1.Epoch)
+	}
+	if len(resp2.OpenObjects) != len(open) {
+		t.Fatalf("expected %d open objects, got %d", len(open), len(resp2.OpenObjects))
+	}
+	for i := range open {
+		if resp2.OpenObjects[i].Id != open[i].Id {
+			t.Errorf("open object %d: expected %s, got %s", i, open[i].Id, resp2.OpenObjects[i].Id)
+		}
+	}
+	if _, err := c.HeartbeatMount(ctx, resp1.MountId[:]); !errors.Is(err, ErrNotMounted) {
+		t.Fatalf("expected ErrNotMounted, got %v", err)
+	}
+}
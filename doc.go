@@ -4,9 +4,15 @@
 //   - Controller: Coordinates object placement, mounts, and repairs
 //   - Blob: Stores object data on local disks
 //
+// The controllers run as a replicated group; ControllerClient is given all of
+// their addresses and fails over between them. A MountSession mounts a store
+// and keeps the mount's lease alive for as long as the store is in use.
+//
 // Usage:
 //
-//	ctrl := basaltclient.NewControllerClient(addr)
+//	parsed, err := basaltclient.ParsePath(path, localZone, resolver)
+//	ctrl := basaltclient.NewControllerClient(parsed.Controllers)
+//	mount := basaltclient.NewMountSession(ctx, ctrl, instanceID, zone, clusterID, storeID)
 //	blobCtrl := basaltclient.NewBlobControlClient(grpcAddr)
 //	blobData := basaltclient.NewBlobDataClient(dataAddr)
 package basaltclient
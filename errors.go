@@ -0,0 +1,100 @@
+package basaltclient
+
+import (
+	"github.com/cockroachdb/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsRetryable reports whether err is a transient failure that may succeed if
+// the operation is retried: a controller or blob server that is unavailable,
+// overloaded, or aborted the request, or a failed connection to a blob
+// server's data endpoint. Retrying is only safe if the operation is
+// idempotent.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, errConnFailed) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNotFound reports whether err reports that an object, directory, or entry
+// does not exist, whether returned by the controller, a blob server's control
+// endpoint, or its data endpoint.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrNotFound) || status.Code(err) == codes.NotFound
+}
+
+// Errors returned by the controller and blob control clients, in addition to
+// the data protocol's ErrNotFound, ErrAlreadyExists and ErrSealed, which they
+// also return. The gRPC status of the underlying error is preserved, so
+// status.Code still reports the original code.
+var (
+	// ErrNotMounted is returned for an operation that requires holding a
+	// mount for a directory, by a caller that does not hold it.
+	ErrNotMounted = errors.New("directory not mounted")
+	// ErrDirectoryNotEmpty is returned when removing a directory that still
+	// has entries.
+	ErrDirectoryNotEmpty = errors.New("directory not empty")
+	// ErrMountConflict is returned when mounting a store that is already
+	// mounted by another instance.
+	ErrMountConflict = errors.New("store mounted by another instance")
+	// ErrFenced is returned for a mutating operation made under a mount
+	// that has since been taken over by another instance.
+	ErrFenced = errors.New("mount fenced by a newer mount")
+)
+
+// errorInfoReasons maps the reasons that servers report in an
+// errdetails.ErrorInfo to the corresponding errors.
+var errorInfoReasons = map[string]error{
+	"NOT_FOUND":           ErrNotFound,
+	"ALREADY_EXISTS":      ErrAlreadyExists,
+	"SEALED":              ErrSealed,
+	"NOT_MOUNTED":         ErrNotMounted,
+	"DIRECTORY_NOT_EMPTY": ErrDirectoryNotEmpty,
+	"MOUNT_CONFLICT":      ErrMountConflict,
+	"FENCED":              ErrFenced,
+}
+
+// codeErrors maps gRPC codes that identify an error on their own to the
+// corresponding errors.
+var codeErrors = map[codes.Code]error{
+	codes.NotFound:      ErrNotFound,
+	codes.AlreadyExists: ErrAlreadyExists,
+}
+
+// translateError marks a gRPC error with the error it corresponds to, if
+// any: the error for the reason of an errdetails.ErrorInfo in its details, or
+// failing that, the error for its code.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			if mark, ok := errorInfoReasons[info.Reason]; ok {
+				return errors.Mark(err, mark)
+			}
+		}
+	}
+	if mark, ok := codeErrors[s.Code()]; ok {
+		return errors.Mark(err, mark)
+	}
+	return err
+}
@@ -0,0 +1,134 @@
+package basaltclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failureReportSink is the subset of ControllerClient used by
+// FailureReporter. This interface exists primarily for testing; production
+// code uses *ControllerClient.
+type failureReportSink interface {
+	ReportFailure(
+		ctx context.Context,
+		objectID []byte,
+		addr string,
+		kind basaltpb.ReplicaFailureKind,
+		detail string,
+	) error
+}
+
+const (
+	defaultFailureReportInterval = time.Minute
+	failureReportTimeout         = 10 * time.Second
+	// failureReportPruneSize is the number of tracked replicas above which
+	// entries older than the reporting interval are pruned.
+	failureReportPruneSize = 1024
+)
+
+// FailureReporter reports persistent replica failures to the controller so
+// that it can schedule repairs. It is used by QuorumWriter (see
+// WithQuorumFailureReporter) and RecoverQuorumPrefix (see
+// WithRecoveryFailureReporter), and may be shared between any number of them.
+//
+// Reports are sent in the background and rate limited: a failure of the same
+// replica of the same object is reported at most once per interval. Errors
+// sending a report are logged and otherwise ignored.
+//
+// FailureReporter is safe for concurrent use.
+type FailureReporter struct {
+	sink     failureReportSink
+	logger   Logger
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[failureKey]time.Time // time of the last report of each replica
+}
+
+type failureKey struct {
+	objectID ObjectID
+	addr     string
+}
+
+// NewFailureReporter creates a FailureReporter that reports to c at most once
+// per interval for each replica. An interval of 0 uses the default of one
+// minute.
+func NewFailureReporter(c *ControllerClient, interval time.Duration) *FailureReporter {
+	return newFailureReporter(c, c.logger, interval)
+}
+
+func newFailureReporter(
+	sink failureReportSink, logger Logger, interval time.Duration,
+) *FailureReporter {
+	if interval <= 0 {
+		interval = defaultFailureReportInterval
+	}
+	return &FailureReporter{
+		sink:     sink,
+		logger:   logger,
+		interval: interval,
+		last:     make(map[failureKey]time.Time),
+	}
+}
+
+// Report reports that the replica of objectID held by the blob server at
+// addr failed with err. It does not block; the report is dropped if the same
+// replica was reported within the interval.
+func (r *FailureReporter) Report(objectID ObjectID, addr string, err error) {
+	if !r.allow(failureKey{objectID: objectID, addr: addr}) {
+		return
+	}
+	kind := classifyFailure(err)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), failureReportTimeout)
+		defer cancel()
+		if rerr := r.sink.ReportFailure(ctx, objectID[:], addr, kind, err.Error()); rerr != nil {
+			r.logger.Errorf("basaltclient: reporting failure of replica %s of %s: %v",
+				addr, objectID, rerr)
+		}
+	}()
+}
+
+// allow records a report for key, returning false if key was reported within
+// the interval.
+func (r *FailureReporter) allow(key failureKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if last, ok := r.last[key]; ok && now.Sub(last) < r.interval {
+		return false
+	}
+	if len(r.last) >= failureReportPruneSize {
+		for k, last := range r.last {
+			if now.Sub(last) >= r.interval {
+				delete(r.last, k)
+			}
+		}
+	}
+	r.last[key] = now
+	return true
+}
+
+// classifyFailure maps a replica error to the kind of failure reported to the
+// controller.
+func classifyFailure(err error) basaltpb.ReplicaFailureKind {
+	switch {
+	case errors.Is(err, ErrDivergentReplicas):
+		return basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_DIVERGENT
+	case errors.Is(err, ErrIOError):
+		return basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_IO_ERROR
+	case errors.Is(err, errReplicaQueueLimitExceeded):
+		return basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_SLOW
+	case errors.Is(err, errConnFailed), errors.Is(err, errReplayBufferExceeded),
+		status.Code(err) == codes.Unavailable:
+		return basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNREACHABLE
+	default:
+		return basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNSPECIFIED
+	}
+}
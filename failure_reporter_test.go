@@ -0,0 +1,98 @@
+package basaltclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type failureReport struct {
+	objectID ObjectID
+	addr     string
+	kind     basaltpb.ReplicaFailureKind
+}
+
+// chanFailureSink sends the reports it receives on a channel.
+type chanFailureSink chan failureReport
+
+func (s chanFailureSink) ReportFailure(
+	ctx context.Context,
+	objectID []byte,
+	addr string,
+	kind basaltpb.ReplicaFailureKind,
+	detail string,
+) error {
+	s <- failureReport{objectID: ObjectID(objectID), addr: addr, kind: kind}
+	return nil
+}
+
+func expectReport(t *testing.T, reports chanFailureSink, want failureReport) {
+	t.Helper()
+	select {
+	case got := <-reports:
+		if got != want {
+			t.Fatalf("expected report %+v, got %+v", want, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for report %+v", want)
+	}
+}
+
+func expectNoReport(t *testing.T, reports chanFailureSink) {
+	t.Helper()
+	select {
+	case got := <-reports:
+		t.Fatalf("unexpected report %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFailureReporterRateLimit(t *testing.T) {
+	reports := make(chanFailureSink, 10)
+	r := newFailureReporter(reports, NopLogger, 200*time.Millisecond)
+	unspecified := basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNSPECIFIED
+
+	r.Report(ObjectID{1}, "addr0", errors.New("boom"))
+	expectReport(t, reports, failureReport{ObjectID{1}, "addr0", unspecified})
+
+	// Repeated failures of the same replica are dropped, but other replicas
+	// are still reported.
+	r.Report(ObjectID{1}, "addr0", errors.New("boom"))
+	expectNoReport(t, reports)
+	r.Report(ObjectID{1}, "addr1", errors.New("boom"))
+	expectReport(t, reports, failureReport{ObjectID{1}, "addr1", unspecified})
+	r.Report(ObjectID{2}, "addr0", errors.New("boom"))
+	expectReport(t, reports, failureReport{ObjectID{2}, "addr0", unspecified})
+
+	time.Sleep(200 * time.Millisecond)
+	r.Report(ObjectID{1}, "addr0", errors.New("boom"))
+	expectReport(t, reports, failureReport{ObjectID{1}, "addr0", unspecified})
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want basaltpb.ReplicaFailureKind
+	}{
+		{errors.New("boom"), basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNSPECIFIED},
+		{errors.Wrap(ErrIOError, "appending"), basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_IO_ERROR},
+		{ErrDivergentReplicas, basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_DIVERGENT},
+		{errReplicaQueueLimitExceeded, basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_SLOW},
+		{errReplayBufferExceeded, basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNREACHABLE},
+		{errors.Mark(errors.New("reset"), errConnFailed), basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNREACHABLE},
+		{
+			errors.Wrap(status.Error(codes.Unavailable, "down"), "statting"),
+			basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNREACHABLE,
+		},
+	}
+	for _, tt := range tests {
+		if got := classifyFailure(tt.err); got != tt.want {
+			t.Errorf("classifyFailure(%v): got %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
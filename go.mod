@@ -7,6 +7,7 @@ require (
 	github.com/cockroachdb/errors v1.12.0
 	github.com/gogo/protobuf v1.3.2
 	github.com/google/uuid v1.6.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251029180050-ab9386a59fda
 	google.golang.org/grpc v1.57.2
 	google.golang.org/protobuf v1.36.11
 )
@@ -26,5 +27,4 @@ require (
 	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 	golang.org/x/text v0.31.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20251029180050-ab9386a59fda // indirect
 )
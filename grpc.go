@@ -0,0 +1,65 @@
+package basaltclient
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// dialGRPC creates a gRPC connection to addr whose errors are translated
+// into the package's errors (see translateError).
+func dialGRPC(addr string) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(translateUnaryErrors),
+		grpc.WithChainStreamInterceptor(translateStreamErrors),
+	)
+}
+
+// translateUnaryErrors is a unary client interceptor that translates errors.
+func translateUnaryErrors(
+	ctx context.Context,
+	method string,
+	req, reply any,
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	return translateError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// translateStreamErrors is a stream client interceptor that translates
+// errors, including those received on the stream.
+func translateStreamErrors(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return translatingStream{stream}, nil
+}
+
+// translatingStream is a grpc.ClientStream that translates errors.
+type translatingStream struct {
+	grpc.ClientStream
+}
+
+func (s translatingStream) SendMsg(m any) error {
+	return translateError(s.ClientStream.SendMsg(m))
+}
+
+func (s translatingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		return err
+	}
+	return translateError(err)
+}
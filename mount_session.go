@@ -0,0 +1,216 @@
+package basaltclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+)
+
+// mountUnmountTimeout bounds the Unmount sent by MountSession.Close.
+const mountUnmountTimeout = 10 * time.Second
+
+// MountSession is a mount of a store directory whose lease is kept alive by
+// heartbeating the controller in the background.
+//
+// If the lease cannot be renewed, because the controller reports that the
+// mount has expired, been unmounted, or been taken over, or because no
+// heartbeat has succeeded within the lease duration, the session is lost: the
+// channel returned by Lost is closed and heartbeating stops. Once the session
+// is lost, another instance may mount the store, and the caller must stop
+// writing to it.
+//
+// MountSession is safe for concurrent use.
+type MountSession struct {
+	c        *ControllerClient
+	resp     *basaltpb.MountResponse
+	interval time.Duration // 0 uses a third of the lease duration
+	takeover bool
+
+	ctx    context.Context // canceled by Close
+	cancel context.CancelFunc
+	done   chan struct{} // closed when heartbeating stops
+	lost   chan struct{}
+
+	mu     sync.Mutex
+	err    error // why the session was lost
+	closed bool
+}
+
+// MountSessionOption configures a MountSession.
+type MountSessionOption func(*MountSession)
+
+// WithMountHeartbeatInterval sets the interval between heartbeats. The
+// default is a third of the lease duration granted by the controller.
+func WithMountHeartbeatInterval(d time.Duration) MountSessionOption {
+	return func(s *MountSession) {
+		s.interval = d
+	}
+}
+
+// WithMountTakeover makes the session take over the store's mount if it is
+// mounted by another instance, as ControllerClient.TakeoverMount does. The
+// objects the previous instance may have been writing are then reported by
+// OpenObjects.
+func WithMountTakeover() MountSessionOption {
+	return func(s *MountSession) {
+		s.takeover = true
+	}
+}
+
+// NewMountSession mounts the store directory identified by clusterID and
+// storeID for instanceID (see ControllerClient.Mount) and starts
+// heartbeating the mount's lease. The caller must call Close to unmount.
+func NewMountSession(
+	ctx context.Context,
+	c *ControllerClient,
+	instanceID string,
+	zone string,
+	clusterID []byte,
+	storeID []byte,
+	opts ...MountSessionOption,
+) (*MountSession, error) {
+	s := &MountSession{
+		c:    c,
+		done: make(chan struct{}),
+		lost: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	mount := c.Mount
+	if s.takeover {
+		mount = c.TakeoverMount
+	}
+	start := time.Now()
+	resp, err := mount(ctx, instanceID, zone, clusterID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	s.resp = resp
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go s.heartbeatLoop(start, time.Duration(resp.LeaseDurationNanos))
+	return s, nil
+}
+
+// MountID returns the ID of the mount.
+func (s *MountSession) MountID() basaltpb.UUID {
+	return s.resp.MountId
+}
+
+// DirectoryID returns the ID of the mounted store's root directory.
+func (s *MountSession) DirectoryID() basaltpb.UUID {
+	return s.resp.DirectoryId
+}
+
+// WriteToken returns the token for authenticating writes with blob servers.
+func (s *MountSession) WriteToken() []byte {
+	return s.resp.WriteToken
+}
+
+// OpenObjects returns the unsealed objects in the store when its mount was
+// taken over, which should be recovered before they are relied on. It is
+// only populated with WithMountTakeover.
+func (s *MountSession) OpenObjects() []*basaltpb.ObjectMeta {
+	return s.resp.OpenObjects
+}
+
+// Lost returns a channel that is closed when the session is lost.
+func (s *MountSession) Lost() <-chan struct{} {
+	return s.lost
+}
+
+// Err returns why the session was lost, or nil if it has not been. The
+// error is marked with ErrNotMounted.
+func (s *MountSession) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close stops heartbeating and unmounts the store, unless the session has
+// already been lost. Subsequent calls return nil.
+func (s *MountSession) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	<-s.done
+	select {
+	case <-s.lost:
+		return nil
+	default:
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mountUnmountTimeout)
+	defer cancel()
+	if err := s.c.Unmount(ctx, s.resp.MountId[:]); err != nil && !errors.Is(err, ErrNotMounted) {
+		return err
+	}
+	return nil
+}
+
+// heartbeatLoop renews the lease, which was granted for lease at or after
+// start, until the session is closed or lost. A lease of 0 never expires and
+// is not renewed.
+func (s *MountSession) heartbeatLoop(start time.Time, lease time.Duration) {
+	defer close(s.done)
+	if lease <= 0 {
+		return
+	}
+	expires := start.Add(lease)
+	t := time.NewTimer(s.heartbeatInterval(lease))
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-s.ctx.Done():
+			return
+		}
+
+		sent := time.Now()
+		ctx, cancel := context.WithDeadline(s.ctx, expires)
+		renewed, err := s.c.HeartbeatMount(ctx, s.resp.MountId[:])
+		cancel()
+		switch {
+		case err == nil:
+			if renewed > 0 {
+				lease = renewed
+			}
+			expires = sent.Add(lease)
+		case s.ctx.Err() != nil:
+			return
+		case errors.Is(err, ErrNotMounted):
+			s.setLost(errors.Wrap(err, "mount lost"))
+			return
+		case !time.Now().Before(expires):
+			s.setLost(errors.Mark(errors.Wrap(err, "mount lease expired"), ErrNotMounted))
+			return
+		default:
+			s.c.logger.Errorf("basaltclient: heartbeating mount %s: %v", s.resp.MountId, err)
+		}
+		t.Reset(s.heartbeatInterval(lease))
+	}
+}
+
+func (s *MountSession) heartbeatInterval(lease time.Duration) time.Duration {
+	if s.interval > 0 {
+		return s.interval
+	}
+	return lease / 3
+}
+
+// setLost records that the session was lost because of err.
+func (s *MountSession) setLost(err error) {
+	s.c.logger.Errorf("basaltclient: %v", err)
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+	close(s.lost)
+}
@@ -0,0 +1,142 @@
+package basaltclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+)
+
+func newTestMountSession(t *testing.T, ctrl *testController) (*ControllerClient, *MountSession) {
+	t.Helper()
+	c, err := NewControllerClient([]string{ctrl.addr},
+		ControllerClientConfig{Logger: NopLogger, Retry: RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	s, err := NewMountSession(context.Background(), c, "instance", "zone",
+		make([]byte, 16), make([]byte, 16), WithMountHeartbeatInterval(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, s
+}
+
+func waitForLost(t *testing.T, s *MountSession) {
+	t.Helper()
+	select {
+	case <-s.Lost():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the session to be lost")
+	}
+	if err := s.Err(); !errors.Is(err, ErrNotMounted) {
+		t.Fatalf("expected ErrNotMounted, got %v", err)
+	}
+}
+
+func TestMountSessionHeartbeat(t *testing.T) {
+	ctrl := newTestController(t, true)
+	ctrl.setLease(200 * time.Millisecond)
+	_, s := newTestMountSession(t, ctrl)
+
+	// Heartbeats keep the session alive for several lease durations.
+	waitFor(t, func() error {
+		if n, _ := ctrl.heartbeats(s.MountID()); n < 20 {
+			return errors.Newf("timed out waiting for heartbeats (got %d)", n)
+		}
+		return nil
+	})
+	select {
+	case <-s.Lost():
+		t.Fatalf("session lost: %v", s.Err())
+	default:
+	}
+
+	// Close unmounts.
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ctrl.heartbeats(s.MountID()); ok {
+		t.Fatal("expected the store to be unmounted")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMountSessionExpired(t *testing.T) {
+	ctrl := newTestController(t, true)
+	ctrl.setLease(time.Minute)
+	_, s := newTestMountSession(t, ctrl)
+
+	// The controller expires the mount; the next heartbeat finds out.
+	ctrl.expire(s.MountID())
+	waitForLost(t, s)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMountSessionLeaseLapses(t *testing.T) {
+	ctrl := newTestController(t, true)
+	ctrl.setLease(200 * time.Millisecond)
+	_, s := newTestMountSession(t, ctrl)
+
+	// While the controller is unreachable, the session is lost once the
+	// lease lapses, even though the controller never reports it.
+	ctrl.setLeader(false)
+	start := time.Now()
+	waitForLost(t, s)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("session lost after %s, expected about the lease duration", elapsed)
+	}
+}
+
+func TestMountSessionNoLease(t *testing.T) {
+	ctrl := newTestController(t, true)
+	_, s := newTestMountSession(t, ctrl)
+
+	// A mount without a lease is never heartbeated.
+	time.Sleep(100 * time.Millisecond)
+	if n, _ := ctrl.heartbeats(s.MountID()); n != 0 {
+		t.Fatalf("expected no heartbeats, got %d", n)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMountSessionTakeover(t *testing.T) {
+	ctrl := newTestController(t, true)
+	ctrl.setLease(time.Minute)
+	open := []*basaltpb.ObjectMeta{{Id: basaltpb.NewUUID()}}
+	ctrl.setOpenObjects(open)
+	c, s1 := newTestMountSession(t, ctrl)
+
+	// Another instance takes over the mount, and the first session finds
+	// out it was lost.
+	s2, err := NewMountSession(context.Background(), c, "instance2", "zone",
+		make([]byte, 16), make([]byte, 16),
+		WithMountHeartbeatInterval(20*time.Millisecond), WithMountTakeover())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s2.OpenObjects(); len(got) != 1 || got[0].Id != open[0].Id {
+		t.Fatalf("expected open objects %v, got %v", open, got)
+	}
+	waitForLost(t, s1)
+	if err := s1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-s2.Lost():
+		t.Fatalf("session lost: %v", s2.Err())
+	default:
+	}
+	if err := s2.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
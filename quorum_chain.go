@@ -0,0 +1,201 @@
+package basaltclient
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// errChainEmpty is returned for writes in chain replication mode once every
+// replica has been dropped from the chain.
+var errChainEmpty = errors.New("no replicas left in the chain")
+
+// chainClient is the client of the chain worker in chain replication mode
+// (see WithQuorumChainReplication). It sends each request to the first
+// replica left in the chain, using that replica's worker's client, and
+// reconfigures the chain when a replica fails.
+//
+// chainClient is only used by the chain worker's run goroutine, which is
+// also the only user of the replicas' clients.
+type chainClient struct {
+	w *QuorumWriter
+}
+
+var _ quorumClient = (*chainClient)(nil)
+
+// Append implements quorumClient.
+func (c *chainClient) Append(id ObjectID, offset uint64, data []byte) error {
+	return c.appendChain(id, offset, data, false /* sync */)
+}
+
+// AppendSync implements quorumClient.
+func (c *chainClient) AppendSync(id ObjectID, offset uint64, data []byte) error {
+	return c.appendChain(id, offset, data, true /* sync */)
+}
+
+// appendChain sends a request along the chain and returns once every replica
+// left in the chain has acknowledged it.
+//
+// A replica that fails is ejected, which drops it from the chain. If a
+// downstream replica fails, the replicas before it hold the data, and the
+// request is resent to the part of the chain after it so that every replica
+// left in the chain ends up holding the data. If the first replica fails with
+// a protocol error, it did not forward the request, which is resent to the
+// rest of the chain. A connection error from the first replica is returned,
+// leaving the worker to reconnect, resync and replay.
+func (c *chainClient) appendChain(id ObjectID, offset uint64, data []byte, sync bool) error {
+	c.w.mu.Lock()
+	pending := c.w.chainMembersLocked()
+	c.w.mu.Unlock()
+
+	head := true // pending starts at the head of the chain
+	for len(pending) > 0 {
+		next := make([]string, len(pending)-1)
+		for i, rw := range pending[1:] {
+			next[i] = rw.replica.Addr
+		}
+		err := pending[0].client.AppendChain(id, offset, next, data, sync)
+		if err == nil {
+			return nil
+		}
+
+		var chainErr *ChainError
+		switch {
+		case errors.As(err, &chainErr) && chainErr.Index < len(next):
+			failed := pending[1+chainErr.Index]
+			c.w.eject(failed, err)
+			pending = pending[2+chainErr.Index:]
+			head = false
+		case head && errors.Is(err, errConnFailed):
+			return err
+		default:
+			c.w.eject(pending[0], err)
+			pending = pending[1:]
+		}
+	}
+
+	c.w.mu.Lock()
+	defer c.w.mu.Unlock()
+	if len(c.w.chainMembersLocked()) == 0 {
+		return errChainEmpty
+	}
+	return nil
+}
+
+// resync reconciles the replicas left in the chain after the connection to
+// the first replica failed, before the worker replays queue, the writes from
+// offset written on that the chain has not acknowledged. The request that was
+// in flight may have reached any prefix of the chain, so each replica's data
+// is read back and compared with the queue. Replicas holding less than the
+// most any replica holds are caught up directly, and the number of bytes of
+// the queue every replica left in the chain now holds is returned.
+//
+// As in appendChain, a connection error from the first replica is returned,
+// and other replicas that fail are ejected.
+func (c *chainClient) resync(id ObjectID, written int64, queue []quorumRequest) (int64, error) {
+	c.w.mu.Lock()
+	members := c.w.chainMembersLocked()
+	c.w.mu.Unlock()
+
+	held := make([]int64, len(members))
+	var most int64
+	for i, rw := range members {
+		n, err := heldPrefix(rw.client, id, written, queue, rw.replica.Addr)
+		if err != nil {
+			if i == 0 && errors.Is(err, errConnFailed) {
+				return 0, err
+			}
+			c.w.eject(rw, err)
+			held[i] = -1
+			continue
+		}
+		held[i] = n
+		most = max(most, n)
+	}
+	for i, rw := range members {
+		if held[i] < 0 || held[i] == most {
+			continue
+		}
+		err := appendQueued(rw.client, id, queue, written+held[i], written+most)
+		if err != nil {
+			if i == 0 && errors.Is(err, errConnFailed) {
+				return 0, err
+			}
+			c.w.eject(rw, err)
+		}
+	}
+
+	c.w.mu.Lock()
+	defer c.w.mu.Unlock()
+	if len(c.w.chainMembersLocked()) == 0 {
+		return 0, errChainEmpty
+	}
+	return most, nil
+}
+
+// appendQueued appends the part of the queued writes between offsets from and
+// to directly to the replica behind client.
+func appendQueued(client quorumClient, id ObjectID, queue []quorumRequest, from, to int64) error {
+	for _, req := range queue {
+		start := int64(req.offset)
+		end := start + int64(len(req.data))
+		lo, hi := max(from, start), min(to, end)
+		if lo >= hi {
+			continue
+		}
+		if err := client.Append(id, uint64(lo), req.data[lo-start:hi-start]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendChain implements quorumClient. The chain worker is not a replica, so
+// it is never a member of a chain.
+func (c *chainClient) AppendChain(
+	id ObjectID, offset uint64, next []string, data []byte, sync bool,
+) error {
+	return errors.New("the chain client cannot be a member of a chain")
+}
+
+// Read implements quorumClient. The chain worker never reads.
+func (c *chainClient) Read(id ObjectID, offset uint64, p []byte) (int, error) {
+	return 0, errors.New("reads are not supported by the chain client")
+}
+
+// SetRequestTimeout implements quorumClient. The replicas' clients are
+// configured with the writer's timeout when their workers are created.
+func (c *chainClient) SetRequestTimeout(d time.Duration) {}
+
+// abort implements quorumClient by aborting the clients of every replica in
+// the chain.
+func (c *chainClient) abort() {
+	for _, rw := range c.workers() {
+		rw.client.abort()
+	}
+}
+
+// Close implements quorumClient by closing the connections to every replica
+// in the chain, so that the next request reconnects.
+func (c *chainClient) Close() error {
+	var err error
+	for _, rw := range c.workers() {
+		err = errors.CombineErrors(err, rw.client.Close())
+	}
+	return err
+}
+
+// workers returns the workers of the replicas in the chain, including those
+// that have been dropped from it. The clients of the other replicas belong to
+// their own run goroutines.
+func (c *chainClient) workers() []*quorumReplicaWorker {
+	c.w.mu.Lock()
+	defer c.w.mu.Unlock()
+	var workers []*quorumReplicaWorker
+	for _, rw := range c.w.workers {
+		if rw.inChain {
+			workers = append(workers, rw)
+		}
+	}
+	return workers
+}
@@ -0,0 +1,406 @@
+package basaltclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+)
+
+// ErrDivergentReplicas is returned by RecoverQuorumPrefix when replicas hold
+// different bytes at the same offset of the recovered prefix.
+var ErrDivergentReplicas = errors.New("replicas hold different data")
+
+// RecoveryOption configures RecoverQuorumPrefix.
+type RecoveryOption func(*recoveryConfig)
+
+type recoveryConfig struct {
+	rule     QuorumRule
+	repair   bool
+	reporter *FailureReporter
+	control  func(addr string) (blobController, error)
+	factory  quorumClientFactory
+}
+
+// WithRecoveryQuorumRule sets the rule the object was written with, which
+// determines how much of it may have been acknowledged. It should match the
+// rule given to the QuorumWriter, including any restriction to storage tiers
+// (see TieredQuorum). The default is MajorityQuorum.
+func WithRecoveryQuorumRule(rule QuorumRule) RecoveryOption {
+	return func(c *recoveryConfig) {
+		if rule != nil {
+			c.rule = rule
+		}
+	}
+}
+
+// WithRecoveryRepair makes recovery append the missing part of the recovered
+// prefix to reachable replicas that fall short of it, so that the prefix is
+// durable on every reachable replica.
+func WithRecoveryRepair() RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.repair = true
+	}
+}
+
+// WithRecoveryFailureReporter reports replicas that cannot be reached, hold
+// divergent data, or fail to be repaired or read to the controller through
+// r, so that they can be repaired.
+func WithRecoveryFailureReporter(r *FailureReporter) RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.reporter = r
+	}
+}
+
+// RecoveredReplica describes a replica as found by RecoverQuorumPrefix.
+type RecoveredReplica struct {
+	// Addr is the address of the blob server holding the replica.
+	Addr string
+	// Size is the size of the replica before any repair, or -1 if it could
+	// not be reached. A Size larger than the recovered size means the replica
+	// holds a tail that was never acknowledged.
+	Size int64
+	// Sealed reports whether the replica is sealed.
+	Sealed bool
+	// Repaired reports whether the replica was brought up to the recovered
+	// size by WithRecoveryRepair.
+	Repaired bool
+	// Err is the error encountered statting or repairing the replica.
+	Err error
+}
+
+// RecoveredObject is a read-only view of the prefix of an unsealed object
+// that RecoverQuorumPrefix determined to be safe to read. It implements
+// io.ReaderAt and is safe for concurrent use.
+type RecoveredObject struct {
+	objectID ObjectID
+	size     int64
+	durable  bool
+	replicas []RecoveredReplica
+	reporter *FailureReporter
+
+	mu     sync.Mutex // serializes reads on client
+	source string
+	client quorumClient
+}
+
+// RecoverQuorumPrefix determines how much of an unsealed object, typically a
+// WAL left behind by a crashed writer, is safe to read.
+//
+// Every replica in meta.Replicas is stat'd through resolve. The recovered
+// prefix is the longest one that a quorum of replicas may have acknowledged:
+// a replica that cannot be reached is assumed to hold everything, so that no
+// acknowledged write is lost, while a reachable replica only counts for the
+// bytes it actually holds. Bytes beyond the prefix were never acknowledged
+// and are ignored. The prefix is read back from every reachable replica
+// holding it, and ErrDivergentReplicas is returned if any two disagree.
+//
+// The returned object must be closed when no longer needed.
+func RecoverQuorumPrefix(
+	ctx context.Context,
+	meta *basaltpb.ObjectMeta,
+	resolve BlobControlResolver,
+	opts ...RecoveryOption,
+) (*RecoveredObject, error) {
+	cfg := recoveryConfig{
+		rule: MajorityQuorum,
+		control: func(addr string) (blobController, error) {
+			return resolve(addr)
+		},
+		factory: defaultQuorumClientFactory,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return recoverQuorumPrefix(ctx, ObjectID(meta.Id), meta.Replicas, cfg)
+}
+
+func recoverQuorumPrefix(
+	ctx context.Context, id ObjectID, replicas []basaltpb.ReplicaInfo, cfg recoveryConfig,
+) (*RecoveredObject, error) {
+	if len(replicas) == 0 {
+		return nil, errors.New("object has no replicas")
+	}
+	o := &RecoveredObject{
+		objectID: id,
+		replicas: statReplicas(ctx, id, replicas, cfg.control),
+		reporter: cfg.reporter,
+	}
+	for _, r := range o.replicas {
+		if r.Err != nil {
+			o.reportFailure(r.Addr, r.Err)
+		}
+	}
+
+	size, ok := recoverableSize(o.replicas, replicas, cfg.rule)
+	if !ok {
+		return nil, errors.New("no replica could be reached")
+	}
+	o.size = size
+
+	clients := make([]quorumClient, len(replicas))
+	defer func() {
+		for _, c := range clients {
+			if c != nil {
+				_ = c.Close()
+			}
+		}
+	}()
+	for i, r := range o.replicas {
+		if r.Size >= 0 {
+			clients[i] = cfg.factory(r.Addr)
+		}
+	}
+	if err := o.verify(ctx, clients, cfg.repair); err != nil {
+		return nil, err
+	}
+
+	// The prefix is durable if the replicas now holding all of it satisfy
+	// the rule on their own.
+	var holders []basaltpb.ReplicaInfo
+	for i, r := range o.replicas {
+		if r.Size >= size || r.Repaired {
+			holders = append(holders, replicas[i])
+			if o.client == nil && r.Size >= size {
+				o.source = r.Addr
+				o.client = clients[i]
+				clients[i] = nil
+			}
+		}
+	}
+	o.durable = cfg.rule(holders, replicas)
+	return o, nil
+}
+
+// statReplicas stats each replica concurrently.
+func statReplicas(
+	ctx context.Context,
+	id ObjectID,
+	replicas []basaltpb.ReplicaInfo,
+	control func(addr string) (blobController, error),
+) []RecoveredReplica {
+	result := make([]RecoveredReplica, len(replicas))
+	var wg sync.WaitGroup
+	for i, r := range replicas {
+		result[i] = RecoveredReplica{Addr: r.Addr, Size: -1}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctrl, err := control(r.Addr)
+			if err != nil {
+				result[i].Err = errors.Wrapf(err, "resolving control client for %s", r.Addr)
+				return
+			}
+			size, sealed, err := ctrl.Stat(ctx, id)
+			if err != nil {
+				result[i].Err = errors.Wrapf(err, "statting %s", r.Addr)
+				return
+			}
+			result[i].Size = size
+			result[i].Sealed = sealed
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+// recoverableSize returns the largest size held by a reachable replica such
+// that the reachable replicas holding it, together with the unreachable
+// replicas, satisfy rule. It returns false if no replica is reachable.
+func recoverableSize(
+	found []RecoveredReplica, replicas []basaltpb.ReplicaInfo, rule QuorumRule,
+) (int64, bool) {
+	var sizes []int64
+	for _, r := range found {
+		if r.Size >= 0 {
+			sizes = append(sizes, r.Size)
+		}
+	}
+	if len(sizes) == 0 {
+		return 0, false
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+	for _, size := range sizes {
+		var possible []basaltpb.ReplicaInfo
+		for i, r := range found {
+			if r.Size < 0 || r.Size >= size {
+				possible = append(possible, replicas[i])
+			}
+		}
+		if rule(possible, replicas) {
+			return size, true
+		}
+	}
+	return 0, true
+}
+
+// verify reads the recovered prefix from every reachable replica holding
+// part of it and checks that they agree. If repair is set, replicas that fall
+// short of the prefix have the missing bytes appended.
+func (o *RecoveredObject) verify(ctx context.Context, clients []quorumClient, repair bool) error {
+	bufs := make([][]byte, len(clients))
+	for offset := int64(0); offset < o.size; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunkLen := min(o.size-offset, quorumBackfillChunkSize)
+		var ref []byte
+		refIdx := -1
+		for i, r := range o.replicas {
+			if r.Size <= offset || clients[i] == nil {
+				continue
+			}
+			if bufs[i] == nil {
+				bufs[i] = make([]byte, min(o.size, quorumBackfillChunkSize))
+			}
+			n := min(chunkLen, r.Size-offset)
+			chunk := bufs[i][:n]
+			if err := readFull(clients[i], o.objectID, offset, chunk); err != nil {
+				err = errors.Wrapf(err, "reading from %s", r.Addr)
+				o.reportFailure(r.Addr, err)
+				return err
+			}
+			if ref == nil || len(chunk) > len(ref) {
+				if ref != nil && !bytes.Equal(chunk[:len(ref)], ref) {
+					return o.divergenceError(o.replicas[refIdx].Addr, r.Addr, offset)
+				}
+				ref, refIdx = chunk, i
+			} else if !bytes.Equal(chunk, ref[:len(chunk)]) {
+				return o.divergenceError(o.replicas[refIdx].Addr, r.Addr, offset)
+			}
+		}
+		if repair {
+			o.repairChunk(clients, offset, ref)
+		}
+		offset += chunkLen
+	}
+	return nil
+}
+
+// divergenceError returns the error for replicas a and b disagreeing in the
+// chunk at offset. Since it is not known which of them is wrong, both are
+// reported.
+func (o *RecoveredObject) divergenceError(a, b string, offset int64) error {
+	err := errors.Wrapf(ErrDivergentReplicas, "%s and %s differ in the chunk at offset %d", a, b, offset)
+	o.reportFailure(a, err)
+	o.reportFailure(b, err)
+	return err
+}
+
+// reportFailure reports a failure of the replica at addr, if a failure
+// reporter is configured.
+func (o *RecoveredObject) reportFailure(addr string, err error) {
+	if o.reporter != nil {
+		o.reporter.Report(o.objectID, addr, err)
+	}
+}
+
+// repairChunk appends the part of the chunk at offset that each short,
+// reachable replica is missing. A replica that fails to be repaired is left
+// as it is and its error recorded.
+func (o *RecoveredObject) repairChunk(clients []quorumClient, offset int64, chunk []byte) {
+	end := offset + int64(len(chunk))
+	for i := range o.replicas {
+		r := &o.replicas[i]
+		if clients[i] == nil || r.Size >= o.size || r.Err != nil {
+			continue
+		}
+		// The replica's current size is its original size, or offset if an
+		// earlier chunk has already been repaired.
+		cur := max(r.Size, offset)
+		if cur >= end {
+			continue
+		}
+		data := chunk[cur-offset:]
+		var err error
+		if end < o.size {
+			err = clients[i].Append(o.objectID, uint64(cur), data)
+		} else {
+			err = clients[i].AppendSync(o.objectID, uint64(cur), data)
+		}
+		if err != nil {
+			r.Err = errors.Wrapf(err, "repairing %s", r.Addr)
+			o.reportFailure(r.Addr, r.Err)
+			continue
+		}
+		if end == o.size {
+			r.Repaired = true
+		}
+	}
+}
+
+// readFull reads len(p) bytes at offset from the replica behind c.
+func readFull(c quorumClient, id ObjectID, offset int64, p []byte) error {
+	for len(p) > 0 {
+		n, err := c.Read(id, uint64(offset), p)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errors.Newf("unexpected end of data at offset %d", offset)
+		}
+		p = p[n:]
+		offset += int64(n)
+	}
+	return nil
+}
+
+// Size returns the size of the recovered prefix.
+func (o *RecoveredObject) Size() int64 {
+	return o.size
+}
+
+// Durable reports whether the recovered prefix is held by enough replicas to
+// satisfy the quorum rule. It may not be if replicas were unreachable, in
+// which case the prefix includes writes that only the unreachable replicas
+// may have acknowledged.
+func (o *RecoveredObject) Durable() bool {
+	return o.durable
+}
+
+// Replicas returns the state of each replica as found by recovery, in the
+// order of the object's replicas.
+func (o *RecoveredObject) Replicas() []RecoveredReplica {
+	return append([]RecoveredReplica(nil), o.replicas...)
+}
+
+// ReadAt implements io.ReaderAt over the recovered prefix.
+func (o *RecoveredObject) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= o.size {
+		return 0, io.EOF
+	}
+	n := min(int64(len(p)), o.size-off)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.client == nil {
+		return 0, ErrClosed
+	}
+	if err := readFull(o.client, o.objectID, off, p[:n]); err != nil {
+		err = errors.Wrapf(err, "reading from %s", o.source)
+		o.reportFailure(o.source, err)
+		return 0, err
+	}
+	if n < int64(len(p)) {
+		return int(n), io.EOF
+	}
+	return int(n), nil
+}
+
+// Close releases the connection used by ReadAt.
+func (o *RecoveredObject) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.client == nil {
+		return nil
+	}
+	err := o.client.Close()
+	o.client = nil
+	return err
+}
@@ -0,0 +1,150 @@
+package basaltclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
+)
+
+// recoverMem runs recovery against the replicas of c, treating the replicas
+// in unreachable as down.
+func recoverMem(
+	t *testing.T, c *memCluster, n int, unreachable map[string]bool, opts ...RecoveryOption,
+) (*RecoveredObject, error) {
+	t.Helper()
+	replicas := make([]basaltpb.ReplicaInfo, n)
+	for i := range replicas {
+		replicas[i] = basaltpb.ReplicaInfo{Addr: fmt.Sprintf("addr%d", i)}
+	}
+	cfg := recoveryConfig{
+		rule: MajorityQuorum,
+		control: func(addr string) (blobController, error) {
+			if unreachable[addr] {
+				return nil, errors.Newf("%s unreachable", addr)
+			}
+			return memBlobController{c: c, addr: addr}, nil
+		},
+		factory: c.factory,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	o, err := recoverQuorumPrefix(context.Background(), ObjectID{1}, replicas, cfg)
+	if err == nil {
+		t.Cleanup(func() { _ = o.Close() })
+	}
+	return o, err
+}
+
+func readRecovered(t *testing.T, o *RecoveredObject) string {
+	t.Helper()
+	data, err := io.ReadAll(io.NewSectionReader(o, 0, o.Size()+10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRecoverQuorumPrefix(t *testing.T) {
+	for _, repair := range []bool{false, true} {
+		t.Run(fmt.Sprintf("repair=%t", repair), func(t *testing.T) {
+			c := newMemCluster()
+			c.replica("addr0").data = []byte("hello world")
+			c.replica("addr1").data = []byte("hello")
+			c.replica("addr2").data = []byte("hello wor")
+
+			var opts []RecoveryOption
+			if repair {
+				opts = append(opts, WithRecoveryRepair())
+			}
+			o, err := recoverMem(t, c, 3, nil, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Only "hello wor" is held by a majority.
+			if got := readRecovered(t, o); got != "hello wor" {
+				t.Fatalf("expected %q, got %q", "hello wor", got)
+			}
+			if !o.Durable() {
+				t.Fatal("expected prefix to be durable")
+			}
+			want := "hello"
+			if repair {
+				want = "hello wor"
+			}
+			if got := c.replica("addr1").contents(); got != want {
+				t.Fatalf("expected addr1 to hold %q, got %q", want, got)
+			}
+			if got := o.Replicas()[1].Repaired; got != repair {
+				t.Fatalf("expected addr1 repaired=%t, got %t", repair, got)
+			}
+		})
+	}
+}
+
+// TestRecoverQuorumPrefixUnreachable tests that a tail that an unreachable
+// replica may have acknowledged is recovered, and becomes durable once
+// repaired.
+func TestRecoverQuorumPrefixUnreachable(t *testing.T) {
+	for _, repair := range []bool{false, true} {
+		t.Run(fmt.Sprintf("repair=%t", repair), func(t *testing.T) {
+			c := newMemCluster()
+			c.replica("addr0").data = []byte("hello world")
+			c.replica("addr1").data = []byte("hello")
+
+			var opts []RecoveryOption
+			if repair {
+				opts = append(opts, WithRecoveryRepair())
+			}
+			o, err := recoverMem(t, c, 3, map[string]bool{"addr2": true}, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readRecovered(t, o); got != "hello world" {
+				t.Fatalf("expected %q, got %q", "hello world", got)
+			}
+			if o.Durable() != repair {
+				t.Fatalf("expected durable=%t", repair)
+			}
+			if r := o.Replicas()[2]; r.Size != -1 || r.Err == nil {
+				t.Fatalf("expected addr2 to be unreachable, got %+v", r)
+			}
+		})
+	}
+}
+
+func TestRecoverQuorumPrefixDivergent(t *testing.T) {
+	c := newMemCluster()
+	c.replica("addr0").data = []byte("hello")
+	c.replica("addr1").data = []byte("jello")
+	c.replica("addr2").data = []byte("hello")
+
+	_, err := recoverMem(t, c, 3, nil)
+	if !errors.Is(err, ErrDivergentReplicas) {
+		t.Fatalf("expected ErrDivergentReplicas, got %v", err)
+	}
+}
+
+func TestRecoverQuorumPrefixFailureReporter(t *testing.T) {
+	c := newMemCluster()
+	c.replica("addr0").data = []byte("hello")
+	c.replica("addr1").data = []byte("hello")
+	reports := make(chanFailureSink, 10)
+	reporter := newFailureReporter(reports, NopLogger, time.Minute)
+
+	_, err := recoverMem(t, c, 3, map[string]bool{"addr2": true},
+		WithRecoveryFailureReporter(reporter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectReport(t, reports, failureReport{
+		objectID: ObjectID{1},
+		addr:     "addr2",
+		kind:     basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_UNSPECIFIED,
+	})
+}
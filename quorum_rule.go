@@ -0,0 +1,82 @@
+package basaltclient
+
+import (
+	"slices"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+)
+
+// QuorumRule decides whether a write is durable. acked holds the replicas
+// that have durably written the write and everything before it; all holds
+// every replica of the object, including those in acked.
+//
+// A rule must be monotonic: if it is satisfied by some set of acknowledging
+// replicas, it must also be satisfied by any larger set.
+type QuorumRule func(acked, all []basaltpb.ReplicaInfo) bool
+
+// MajorityQuorum is satisfied once a majority of replicas acknowledge. It is
+// the rule used by QuorumWriter unless another is configured.
+func MajorityQuorum(acked, all []basaltpb.ReplicaInfo) bool {
+	return len(acked) > len(all)/2
+}
+
+// MajorityAcrossZones returns a rule that is satisfied once a majority of
+// replicas acknowledge and the acknowledging replicas span at least minZones
+// distinct zones, so that an acknowledged write survives the loss of any
+// minZones-1 zones. Replicas with an empty zone are treated as sharing a
+// single zone.
+func MajorityAcrossZones(minZones int) QuorumRule {
+	return func(acked, all []basaltpb.ReplicaInfo) bool {
+		return MajorityQuorum(acked, all) && countZones(acked) >= minZones
+	}
+}
+
+// countZones returns the number of distinct zones in replicas.
+func countZones(replicas []basaltpb.ReplicaInfo) int {
+	zones := make(map[string]struct{}, len(replicas))
+	for _, r := range replicas {
+		zones[r.Zone] = struct{}{}
+	}
+	return len(zones)
+}
+
+// WriteQuorum returns a rule that is satisfied once at least w replicas
+// acknowledge, regardless of the total number of replicas. A w larger than
+// the number of replicas can never be satisfied.
+func WriteQuorum(w int) QuorumRule {
+	return func(acked, all []basaltpb.ReplicaInfo) bool {
+		return len(acked) >= w
+	}
+}
+
+// TieredQuorum returns a rule that applies rule to only the replicas in the
+// given storage tiers. Replicas in other tiers are still written, but never
+// count towards a quorum, so a slow tier such as HDD does not add to write
+// latency. Replicas with an unspecified tier are treated as SSD replicas.
+func TieredQuorum(rule QuorumRule, tiers ...basaltpb.StorageTier) QuorumRule {
+	return func(acked, all []basaltpb.ReplicaInfo) bool {
+		return rule(filterTiers(acked, tiers), filterTiers(all, tiers))
+	}
+}
+
+// filterTiers returns the replicas in the given storage tiers.
+func filterTiers(
+	replicas []basaltpb.ReplicaInfo, tiers []basaltpb.StorageTier,
+) []basaltpb.ReplicaInfo {
+	filtered := make([]basaltpb.ReplicaInfo, 0, len(replicas))
+	for _, r := range replicas {
+		if inTiers(r, tiers) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// inTiers reports whether replica r is in one of the given storage tiers.
+func inTiers(r basaltpb.ReplicaInfo, tiers []basaltpb.StorageTier) bool {
+	tier := r.Tier
+	if tier == basaltpb.StorageTier_STORAGE_TIER_UNSPECIFIED {
+		tier = basaltpb.StorageTier_STORAGE_TIER_SSD
+	}
+	return slices.Contains(tiers, tier)
+}
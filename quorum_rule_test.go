@@ -0,0 +1,55 @@
+package basaltclient
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/basaltclient/basaltpb"
+)
+
+func TestQuorumRules(t *testing.T) {
+	all := []basaltpb.ReplicaInfo{
+		{Addr: "a1", Zone: "a"},
+		{Addr: "a2", Zone: "a", Tier: basaltpb.StorageTier_STORAGE_TIER_SSD},
+		{Addr: "b1", Zone: "b", Tier: basaltpb.StorageTier_STORAGE_TIER_SSD},
+		{Addr: "c1", Zone: "c", Tier: basaltpb.StorageTier_STORAGE_TIER_HDD},
+		{Addr: "c2", Zone: "c", Tier: basaltpb.StorageTier_STORAGE_TIER_HDD},
+	}
+	subset := func(idx ...int) []basaltpb.ReplicaInfo {
+		var acked []basaltpb.ReplicaInfo
+		for _, i := range idx {
+			acked = append(acked, all[i])
+		}
+		return acked
+	}
+
+	const ssd = basaltpb.StorageTier_STORAGE_TIER_SSD
+	tests := []struct {
+		name  string
+		rule  QuorumRule
+		acked []basaltpb.ReplicaInfo
+		want  bool
+	}{
+		{"majority/none", MajorityQuorum, nil, false},
+		{"majority/minority", MajorityQuorum, subset(0, 1), false},
+		{"majority/majority", MajorityQuorum, subset(0, 1, 2), true},
+		{"zones/one zone", MajorityAcrossZones(2), subset(0, 1), false},
+		{"zones/minority", MajorityAcrossZones(2), subset(0, 2), false},
+		{"zones/majority in two zones", MajorityAcrossZones(2), subset(0, 1, 2), true},
+		{"zones/majority in two of three", MajorityAcrossZones(3), subset(0, 1, 2), false},
+		{"zones/majority in three", MajorityAcrossZones(3), subset(0, 2, 3), true},
+		{"w2/one", WriteQuorum(2), subset(0), false},
+		{"w2/two", WriteQuorum(2), subset(3, 4), true},
+		{"w6/all", WriteQuorum(6), subset(0, 1, 2, 3, 4), false},
+		{"ssd majority/ssd majority", TieredQuorum(MajorityQuorum, ssd), subset(0, 1), true},
+		{"ssd majority/hdd majority", TieredQuorum(MajorityQuorum, ssd), subset(0, 3, 4), false},
+		{"ssd w3/all ssd", TieredQuorum(WriteQuorum(3), ssd), subset(0, 1, 2), true},
+		{"ssd w3/with hdd", TieredQuorum(WriteQuorum(3), ssd), subset(0, 1, 3), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule(tt.acked, all); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
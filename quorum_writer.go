@@ -1,9 +1,13 @@
 package basaltclient
 
 import (
+	"bytes"
+	"context"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
 )
 
 // QuorumWriter implements dedicated WAL writing with quorum semantics.
@@ -12,48 +16,333 @@ import (
 // when a quorum of replicas acknowledge, allowing lagging replicas to
 // catch up asynchronously.
 //
-// QuorumWriter assumes serialized syncs - only one WriteAndSync call is in
-// flight at a time. This matches Pebble's WAL sync behavior.
+// Writes are pipelined: multiple WriteAndSync calls may be in flight at once.
+// Each write is placed at the next offset in call order and queued to every
+// replica in that order, and completes once a quorum of replicas have durably
+// written it along with all prior writes.
+//
+// Alternatively, Write queues data without waiting for it, and Sync waits for
+// everything written so far to become durable. Writes queued for a replica
+// are coalesced into larger requests, and syncs requested while a replica is
+// busy are coalesced into a single synced request, so many small writes from
+// concurrent callers cost few round trips.
+//
+// By default a quorum is a majority of replicas. WithQuorumRule configures
+// other rules, such as requiring acknowledgements from several zones, and
+// WithQuorumWriteQuorum a fixed number of acknowledgements. WithQuorumTiers
+// restricts the quorum to replicas in fast storage tiers, leaving replicas in
+// other tiers to be written asynchronously.
+//
+// By default the writer fans each write out to every replica itself.
+// WithQuorumChainReplication instead sends each write only to the first
+// replica, and the blob servers forward it along the chain of replicas.
 type QuorumWriter struct {
 	objectID ObjectID
-	quorum   int // number of replicas needed for quorum (2 for RF=3)
+	rule     QuorumRule // decides which sets of replicas form a quorum
+	// tiers, if set, restricts the replicas that count towards a quorum to
+	// those in these storage tiers.
+	tiers []basaltpb.StorageTier
+
+	minBackoff       time.Duration // initial reconnect backoff
+	maxBackoff       time.Duration // maximum reconnect backoff
+	replayBufferSize int64         // max bytes queued for a reconnecting replica
+	replicaTimeout   time.Duration // per-request timeout for replicas; 0 means none
+	maxQueueBytes    int64         // max bytes queued for a replica; 0 means no limit
+	maxQueueEntries  int           // max writes queued for a replica; 0 means no limit
+
+	// onEject, if set, is called when a replica is ejected.
+	onEject func(addr string, err error)
+	// reporter, if set, reports ejected replicas to the controller.
+	reporter *FailureReporter
+
+	factory quorumClientFactory
+	// control returns the control client for a replica's blob server. Nil
+	// unless configured with WithQuorumBlobControl.
+	control func(addr string) (blobController, error)
+	// controller records the sealed size of the object. Nil unless configured
+	// with WithQuorumController.
+	controller objectSealer
+	// chain, if set, is the worker that sends every write along the chain of
+	// replicas in chain replication mode. The workers of the replicas in the
+	// chain then only track each replica's progress and do not run.
+	chain *quorumReplicaWorker
+
+	replaceMu sync.Mutex // serializes ReplaceReplica and Seal calls
 
-	mu            sync.Mutex
-	cond          *sync.Cond
-	offset        int64
-	currentOffset int64 // offset of the current in-flight request (for filtering late results)
-	successCount  int
-	failureCount  int
-	lastError     error
-	workers       []*quorumReplicaWorker
-	closed        bool
+	mu     sync.Mutex
+	cond   *sync.Cond // signaled when replica progress changes
+	offset int64      // offset at which the next write will be placed
+	// committed is the end offset of the prefix that has satisfied the
+	// quorum rule. It only advances.
+	committed int64
+	workers   []*quorumReplicaWorker
+	sealing   bool // Seal has been called; no further writes are accepted
+	closed    bool
 }
 
 // quorumClient is the interface used by quorumReplicaWorker to communicate with replicas.
 // This interface exists primarily for testing; production code uses *BlobDataClient.
 type quorumClient interface {
+	Append(id ObjectID, offset uint64, data []byte) error
 	AppendSync(id ObjectID, offset uint64, data []byte) error
+	AppendChain(id ObjectID, offset uint64, next []string, data []byte, sync bool) error
+	Read(id ObjectID, offset uint64, p []byte) (int, error)
+	SetRequestTimeout(d time.Duration)
 	Close() error
+	// abort fails any in-progress request and any later ones. Unlike the
+	// other methods, it may be called concurrently with a request.
+	abort()
+}
+
+// QuorumWriterOption configures a QuorumWriter.
+type QuorumWriterOption func(*QuorumWriter)
+
+const (
+	defaultQuorumMinBackoff       = 10 * time.Millisecond
+	defaultQuorumMaxBackoff       = time.Second
+	defaultQuorumReplayBufferSize = 64 << 20 // 64 MiB
+)
+
+// WithQuorumReconnectBackoff sets the exponential backoff used when
+// reconnecting to a replica after a connection failure. The backoff starts at
+// initial and doubles after each failed attempt, up to limit. The defaults are
+// 10ms and 1s.
+func WithQuorumReconnectBackoff(initial, limit time.Duration) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		if initial > 0 && limit >= initial {
+			w.minBackoff = initial
+			w.maxBackoff = limit
+		}
+	}
+}
+
+// WithQuorumReplayBufferSize sets how many bytes of writes may be retained
+// for a replica while it is reconnecting. A replica that falls further behind
+// is given up on for the rest of the writer's lifetime. The default is 64 MiB.
+func WithQuorumReplayBufferSize(size int64) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		if size > 0 {
+			w.replayBufferSize = size
+		}
+	}
+}
+
+// WithQuorumReplicaTimeout bounds each request sent to a replica. A replica
+// that does not respond within d is treated as having lost its connection: it
+// falls out of the quorum and reconnects. A timed-out request may still have
+// been applied, so the writer reads back what the replica holds before
+// replaying the rest. The default is no timeout.
+func WithQuorumReplicaTimeout(d time.Duration) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		if d > 0 {
+			w.replicaTimeout = d
+		}
+	}
+}
+
+// WithQuorumReplicaQueueLimit bounds the writes queued for each replica that
+// it has not yet acknowledged, by total size and by number of writes. A limit
+// of 0 means no limit, which is the default.
+//
+// When a write would take a replica over either limit, the replica is ejected
+// if it is behind the writes acknowledged so far and a quorum remains without
+// it: its queued writes are released and it receives no further writes for
+// the rest of the writer's lifetime. Otherwise the write waits for the
+// replicas to catch up, so a quorum is never lost to the limit. A single
+// write larger than the size limit is rejected.
+func WithQuorumReplicaQueueLimit(maxBytes int64, maxEntries int) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.maxQueueBytes = max(maxBytes, 0)
+		w.maxQueueEntries = max(maxEntries, 0)
+	}
+}
+
+// WithQuorumEjectionCallback sets a function called when the writer gives up
+// on a replica, with the replica's address and the error that caused it to
+// be ejected. It is not called for replicas removed by ReplaceReplica. The
+// callback runs on its own goroutine and may call methods on the writer.
+func WithQuorumEjectionCallback(fn func(addr string, err error)) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.onEject = fn
+	}
+}
+
+// WithQuorumFailureReporter reports replicas that the writer gives up on to
+// the controller through r, so that they can be repaired. Replicas that
+// recover from a failure by reconnecting are not reported.
+func WithQuorumFailureReporter(r *FailureReporter) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.reporter = r
+	}
+}
+
+// WithQuorumBlobControl sets the resolver used to reach the control endpoint
+// of replicas' blob servers, which is needed by ReplaceReplica.
+func WithQuorumBlobControl(resolve BlobControlResolver) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.control = func(addr string) (blobController, error) {
+			return resolve(addr)
+		}
+	}
+}
+
+// WithQuorumController sets the controller client with which Seal records
+// the final size of the object.
+func WithQuorumController(c *ControllerClient) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		if c != nil {
+			w.controller = c
+		}
+	}
+}
+
+// WithQuorumRule sets the rule deciding when a write is durable. The default
+// is MajorityQuorum. Rules that consider zones, such as MajorityAcrossZones,
+// rely on the Zone of the replicas passed to NewQuorumWriter.
+func WithQuorumRule(rule QuorumRule) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		if rule != nil {
+			w.rule = rule
+		}
+	}
+}
+
+// WithQuorumWriteQuorum sets the number of replicas that must durably write
+// a write before it is acknowledged, in place of a majority. It is shorthand
+// for WithQuorumRule(WriteQuorum(w)). A w of 0 or less is ignored.
+//
+// A write quorum below a majority trades durability for latency. Recovering
+// the object with RecoverQuorumPrefix requires the same rule (see
+// WithRecoveryQuorumRule).
+func WithQuorumWriteQuorum(w int) QuorumWriterOption {
+	return func(qw *QuorumWriter) {
+		if w > 0 {
+			qw.rule = WriteQuorum(w)
+		}
+	}
+}
+
+// WithQuorumTiers restricts the replicas counting towards a quorum to those
+// in the given storage tiers, as recorded in the Tier of the replicas passed
+// to NewQuorumWriter; the quorum rule is applied to those replicas alone (see
+// TieredQuorum). Replicas in other tiers, typically HDD replicas, are written
+// asynchronously: they receive every write and sync, but writes do not wait
+// for them. Replicas with an unspecified tier are treated as SSD replicas.
+//
+// Seal still waits for healthy replicas in every tier to catch up.
+//
+// In chain replication mode (see WithQuorumChainReplication), only the
+// replicas in the given tiers form the chain, so that writes are acknowledged
+// without waiting for the other tiers; the remaining replicas are written
+// separately, as in fan-out mode.
+func WithQuorumTiers(tiers ...basaltpb.StorageTier) QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.tiers = tiers
+	}
+}
+
+// WithQuorumChainReplication enables chain replication: each write is sent
+// only to the first replica, whose blob server appends it and forwards it to
+// the next replica and so on, and the write is acknowledged once the last
+// replica has it. This moves the cost of replication from the client to the
+// blob servers, at the price of a longer round trip. The chain follows the
+// order of the replicas passed to NewQuorumWriter.
+//
+// A write acknowledged by the chain is durable on every replica in it, so
+// QuorumRule still decides whether the replicas left in the chain are enough.
+// When a replica fails, it is ejected and dropped from the chain, and the
+// replicas that followed it are caught up before writes continue on the
+// shortened chain. If the connection to the first replica fails, the writer
+// reconnects, reads back what each replica in the chain holds of the writes
+// that were not acknowledged, catches up the replicas that hold less than
+// others, and replays the rest along the chain.
+//
+// With WithQuorumTiers, the chain is made up of the replicas in the quorum
+// tiers only, and the other replicas are written separately.
+//
+// ReplaceReplica is not supported in chain replication mode.
+func WithQuorumChainReplication() QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.chain = &quorumReplicaWorker{w: w, client: &chainClient{w: w}}
+		w.chain.cond = sync.NewCond(&w.chain.mu)
+	}
 }
 
 // quorumReplicaWorker handles writes to a single replica.
+//
+// Requests stay queued until the replica acknowledges them. If a request fails
+// with a connection error, the worker reconnects with exponential backoff,
+// reads back the queued writes the replica applied even though their
+// acknowledgement was lost, and replays the rest of the queue, so a replica
+// that briefly loses its connection catches up and rejoins the quorum. A
+// replica holding different data than was queued is ejected from the writer,
+// as are replicas that fail with other errors, fall too far behind while
+// reconnecting, or lag behind the quorum at the queue limit.
 type quorumReplicaWorker struct {
-	w      *QuorumWriter // back-reference for reporting results
-	addr   string
-	client quorumClient // dedicated connection, not pooled
+	w       *QuorumWriter // back-reference for reporting results
+	replica basaltpb.ReplicaInfo
+	client  quorumClient // dedicated connection, not pooled
+	inChain bool         // written through the chain worker in chain replication mode
 
-	mu     sync.Mutex
-	cond   *sync.Cond
-	queue  []quorumRequest
-	closed bool
+	// Replica progress, protected by w.mu.
+	written int64 // end offset of the contiguous prefix written
+	durable int64 // end offset of the contiguous prefix written and synced
+	err     error // most recent error; cleared once a write succeeds again
+
+	mu           sync.Mutex
+	cond         *sync.Cond
+	queue        []quorumRequest // requests not yet acknowledged by the replica
+	queuedBytes  int64
+	syncTo       int64 // offset up to which a sync has been requested
+	reconnecting bool  // a connection failure is being retried
+	ejected      bool  // the writer has given up on this replica
+	closed       bool
+
+	// done is closed when the run goroutine exits. It is set, under w.mu,
+	// when the goroutine is started, and is nil for a worker that was never
+	// started, such as the per-replica workers in chain replication mode.
+	done chan struct{}
+
+	// scratch is the buffer into which queued writes are coalesced. It is
+	// only used by the run goroutine.
+	scratch []byte
 }
 
-// quorumRequest represents a single write+sync request.
+// errReplayBufferExceeded is the error recorded for a replica that fell too
+// far behind while reconnecting.
+var errReplayBufferExceeded = errors.New("replica exceeded replay buffer while reconnecting")
+
+// errReplicaQueueLimitExceeded is the error recorded for a replica whose
+// queue exceeded the limit set by WithQuorumReplicaQueueLimit.
+var errReplicaQueueLimitExceeded = errors.New("replica exceeded queue limit")
+
+// errWriteExceedsQueueLimit is returned for a write larger than the queue
+// size limit set by WithQuorumReplicaQueueLimit.
+var errWriteExceedsQueueLimit = errors.New("write exceeds replica queue limit")
+
+// errReplicaReplaced is the error recorded for a replica removed by
+// ReplaceReplica.
+var errReplicaReplaced = errors.New("replica replaced")
+
+// quorumRequest represents a single write.
 type quorumRequest struct {
 	offset uint64
 	data   []byte
 }
 
+// quorumCoalesceLimit is the maximum size of a request formed by coalescing
+// queued writes. Larger writes are sent on their own.
+const quorumCoalesceLimit = 1 << 20 // 1 MiB
+
+// quorumBatch is a run of consecutive queued writes sent to a replica as a
+// single request, or a sync with no data if n is 0.
+type quorumBatch struct {
+	offset int64
+	n      int // number of queued requests covered
+	data   []byte
+	sync   bool
+}
+
 // quorumClientFactory creates a quorumClient for the given address.
 // Used to inject mock clients for testing.
 type quorumClientFactory func(addr string) quorumClient
@@ -64,121 +353,900 @@ func defaultQuorumClientFactory(addr string) quorumClient {
 }
 
 // NewQuorumWriter creates a new quorum writer for the given object and replicas.
-func NewQuorumWriter(objectID ObjectID, replicas []basaltpb.ReplicaInfo) *QuorumWriter {
-	return newQuorumWriterWithFactory(objectID, replicas, defaultQuorumClientFactory)
+func NewQuorumWriter(
+	objectID ObjectID, replicas []basaltpb.ReplicaInfo, opts ...QuorumWriterOption,
+) *QuorumWriter {
+	return newQuorumWriterWithFactory(objectID, replicas, defaultQuorumClientFactory, opts...)
 }
 
 // newQuorumWriterWithFactory creates a new quorum writer using the provided client factory.
 // This is primarily used for testing with mock clients.
 func newQuorumWriterWithFactory(
-	objectID ObjectID, replicas []basaltpb.ReplicaInfo, factory quorumClientFactory,
+	objectID ObjectID,
+	replicas []basaltpb.ReplicaInfo,
+	factory quorumClientFactory,
+	opts ...QuorumWriterOption,
+) *QuorumWriter {
+	w := newQuorumWriter(objectID, replicas, factory, opts...)
+	w.start(0)
+	return w
+}
+
+// OpenQuorumWriter creates a quorum writer that resumes appending to an
+// existing, unsealed object, such as a WAL left behind by a previous process
+// or handed off by another mount. Writes are placed starting at offset, or at
+// the current size of the replicas if offset is negative.
+//
+// Before the writer is returned, every replica is stat'd and must hold
+// exactly offset bytes; if the replicas are unreachable, sealed, or disagree
+// on their size, an error is returned and the caller should recover the
+// object's durable prefix (see RecoverQuorumPrefix) and continue in a new
+// object instead. The writer must be configured with WithQuorumBlobControl.
+func OpenQuorumWriter(
+	ctx context.Context,
+	objectID ObjectID,
+	replicas []basaltpb.ReplicaInfo,
+	offset int64,
+	opts ...QuorumWriterOption,
+) (*QuorumWriter, error) {
+	return openQuorumWriterWithFactory(
+		ctx, objectID, replicas, offset, defaultQuorumClientFactory, opts...)
+}
+
+// openQuorumWriterWithFactory is OpenQuorumWriter using the provided client
+// factory.
+func openQuorumWriterWithFactory(
+	ctx context.Context,
+	objectID ObjectID,
+	replicas []basaltpb.ReplicaInfo,
+	offset int64,
+	factory quorumClientFactory,
+	opts ...QuorumWriterOption,
+) (*QuorumWriter, error) {
+	w := newQuorumWriter(objectID, replicas, factory, opts...)
+	offset, err := w.checkReplicaSizes(ctx, offset)
+	if err != nil {
+		for _, worker := range w.workers {
+			_ = worker.client.Close()
+		}
+		return nil, err
+	}
+	w.start(offset)
+	return w, nil
+}
+
+// newQuorumWriter creates a quorum writer whose workers have not been
+// started.
+func newQuorumWriter(
+	objectID ObjectID,
+	replicas []basaltpb.ReplicaInfo,
+	factory quorumClientFactory,
+	opts ...QuorumWriterOption,
 ) *QuorumWriter {
-	quorum := (len(replicas) / 2) + 1 // majority quorum
 	w := &QuorumWriter{
-		objectID: objectID,
-		quorum:   quorum,
-		workers:  make([]*quorumReplicaWorker, len(replicas)),
+		objectID:         objectID,
+		rule:             MajorityQuorum,
+		minBackoff:       defaultQuorumMinBackoff,
+		maxBackoff:       defaultQuorumMaxBackoff,
+		replayBufferSize: defaultQuorumReplayBufferSize,
+		factory:          factory,
+		workers:          make([]*quorumReplicaWorker, len(replicas)),
 	}
 	w.cond = sync.NewCond(&w.mu)
-
+	for _, opt := range opts {
+		opt(w)
+	}
+	if len(w.tiers) > 0 {
+		w.rule = TieredQuorum(w.rule, w.tiers...)
+	}
 	for i, r := range replicas {
-		worker := &quorumReplicaWorker{
-			w:      w,
-			addr:   r.Addr,
-			client: factory(r.Addr),
+		w.workers[i] = w.newWorker(r)
+		w.workers[i].inChain = w.chain != nil && (len(w.tiers) == 0 || inTiers(r, w.tiers))
+	}
+	return w
+}
+
+// start starts the workers, with every replica durable up to offset.
+func (w *QuorumWriter) start(offset int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.offset = offset
+	w.committed = offset
+	for _, worker := range w.workers {
+		worker.written = offset
+		worker.durable = offset
+	}
+	for _, worker := range w.sendersLocked() {
+		worker.written = offset
+		worker.durable = offset
+		worker.startLocked()
+	}
+}
+
+// sendersLocked returns the workers that send writes: the chain worker and the
+// workers of the replicas outside the chain in chain replication mode, or the
+// worker of every replica otherwise. w.mu must be held.
+func (w *QuorumWriter) sendersLocked() []*quorumReplicaWorker {
+	if w.chain == nil {
+		return w.workers
+	}
+	senders := []*quorumReplicaWorker{w.chain}
+	for _, rw := range w.workers {
+		if !rw.inChain {
+			senders = append(senders, rw)
 		}
-		worker.cond = sync.NewCond(&worker.mu)
-		w.workers[i] = worker
-		go worker.run(objectID)
 	}
+	return senders
+}
 
-	return w
+// ErrReplicasDisagree is returned by OpenQuorumWriter when the replicas of an
+// object do not all hold the expected number of bytes.
+var ErrReplicasDisagree = errors.New("replicas disagree on object size")
+
+// checkReplicaSizes stats every replica and checks that they are unsealed
+// and all hold offset bytes, or the same number of bytes if offset is
+// negative. It returns the agreed size.
+func (w *QuorumWriter) checkReplicaSizes(ctx context.Context, offset int64) (int64, error) {
+	if w.control == nil {
+		return 0, errors.New("opening an object requires WithQuorumBlobControl")
+	}
+	replicas := make([]basaltpb.ReplicaInfo, len(w.workers))
+	for i, worker := range w.workers {
+		replicas[i] = worker.replica
+	}
+	found := statReplicas(ctx, w.objectID, replicas, w.control)
+	for _, r := range found {
+		if r.Err != nil {
+			return 0, r.Err
+		}
+		if r.Sealed {
+			return 0, errors.Wrapf(ErrSealed, "replica %s", r.Addr)
+		}
+	}
+	want := offset
+	if want < 0 {
+		want = found[0].Size
+	}
+	for _, r := range found {
+		if r.Size != want {
+			return 0, errors.Wrapf(ErrReplicasDisagree,
+				"replica %s holds %d bytes, expected %d", r.Addr, r.Size, want)
+		}
+	}
+	return want, nil
+}
+
+// newWorker creates a worker for replica. The caller must start its run loop.
+func (w *QuorumWriter) newWorker(replica basaltpb.ReplicaInfo) *quorumReplicaWorker {
+	worker := &quorumReplicaWorker{
+		w:       w,
+		replica: replica,
+		client:  w.factory(replica.Addr),
+	}
+	worker.cond = sync.NewCond(&worker.mu)
+	if w.replicaTimeout > 0 {
+		worker.client.SetRequestTimeout(w.replicaTimeout)
+	}
+	return worker
 }
 
 // WriteAndSync writes data to all replicas and waits for quorum acknowledgment.
 // It returns nil once a quorum of replicas have successfully written and synced
-// the data. Lagging replicas will continue processing in the background.
+// the data and all data written before it. Lagging replicas will continue
+// processing in the background.
+//
+// If ctx is done before quorum is reached, WriteAndSync returns an error
+// wrapping ctx.Err(). The data remains queued to the replicas at its offset
+// and may still become durable; later writes are placed after it, so a
+// successful later write implies this one is durable too. If ctx is done
+// while the write waits for room in the replicas' queues (see
+// WithQuorumReplicaQueueLimit), the data is not written at all.
 //
-// Only one WriteAndSync call may be in flight at a time.
-func (w *QuorumWriter) WriteAndSync(data []byte) error {
-	offset, workers, err := w.prepareWrite(data)
+// WriteAndSync may be called concurrently; see QuorumWriter.
+func (w *QuorumWriter) WriteAndSync(ctx context.Context, data []byte) error {
+	end, err := w.enqueueWrite(ctx, data, true /* sync */)
 	if err != nil {
 		return err
 	}
+	return w.waitForQuorum(ctx, end)
+}
+
+// Write queues data to be written to all replicas at the next offset and
+// returns without waiting for it to be written. The data is not durable until
+// a subsequent Sync or WriteAndSync returns successfully. The writer retains
+// data until every replica has written it, so the caller must not modify it
+// after calling Write.
+//
+// If the replicas a quorum needs have as many writes queued as
+// WithQuorumReplicaQueueLimit allows, Write blocks until they catch up.
+//
+// Write may be called concurrently with other writes and syncs; see
+// QuorumWriter.
+func (w *QuorumWriter) Write(data []byte) error {
+	_, err := w.enqueueWrite(context.Background(), data, false /* sync */)
+	return err
+}
 
+// Sync waits until a quorum of replicas have durably written all data passed
+// to Write or WriteAndSync before Sync was called. Concurrent calls to Sync
+// share replica round trips.
+//
+// If ctx is done first, Sync returns an error wrapping ctx.Err(); the sync
+// still proceeds in the background.
+func (w *QuorumWriter) Sync(ctx context.Context) error {
+	end, err := w.requestSync()
+	if err != nil {
+		return err
+	}
+	return w.waitForQuorum(ctx, end)
+}
+
+// requestSync asks every replica to sync everything written so far, and
+// returns the offset up to which the replicas will sync.
+func (w *QuorumWriter) requestSync() (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	for _, worker := range w.sendersLocked() {
+		worker.requestSync(w.offset)
+	}
+	return w.offset, nil
+}
+
+// enqueueWrite places data at the next offset and fans it out to all workers,
+// requesting a sync of it if sync is set, and returns the end offset of the
+// write. Returns ErrClosed if the writer is closed, or ErrSealed if it is
+// being sealed. If the write does not fit in the replicas' queues, it waits
+// until it does or ctx is done (see WithQuorumReplicaQueueLimit). The
+// writer's lock is held while enqueueing so that concurrent writes are queued
+// to every replica in offset order.
+func (w *QuorumWriter) enqueueWrite(ctx context.Context, data []byte, sync bool) (int64, error) {
+	if w.maxQueueBytes > 0 && int64(len(data)) > w.maxQueueBytes {
+		return 0, errors.Wrapf(errWriteExceedsQueueLimit,
+			"%d bytes > %d", len(data), w.maxQueueBytes)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	err := w.waitLocked(ctx, func() (bool, error) {
+		if w.sealing {
+			return false, ErrSealed
+		}
+		return w.makeQueueRoomLocked(len(data)), nil
+	})
+	if err != nil {
+		return 0, err
+	}
 	req := quorumRequest{
-		offset: uint64(offset),
+		offset: uint64(w.offset),
 		data:   data,
 	}
-
-	// Fan out to all workers.
-	for _, worker := range workers {
+	w.offset += int64(len(data))
+	for _, worker := range w.sendersLocked() {
 		worker.enqueue(req)
+		if sync {
+			worker.requestSync(w.offset)
+		}
 	}
+	return w.offset, nil
+}
+
+// makeQueueRoomLocked reports whether a write of n bytes can be queued to
+// every replica without exceeding the queue limit. A replica that the write
+// would take over the limit is ejected if it is behind the committed offset
+// and the quorum rule can be satisfied without it; otherwise the write must
+// wait for it to catch up. w.mu must be held.
+func (w *QuorumWriter) makeQueueRoomLocked(n int) bool {
+	if w.maxQueueBytes == 0 && w.maxQueueEntries == 0 {
+		return true
+	}
+	room := true
+	for _, rw := range w.sendersLocked() {
+		if !rw.overQueueLimit(n) {
+			continue
+		}
+		// The chain worker is not a replica, and ejecting it would eject
+		// every replica in the chain.
+		if rw != w.chain && rw.durable < w.committed && w.canLoseLocked(rw) {
+			rw.mu.Lock()
+			rw.ejectLocked(errReplicaQueueLimitExceeded)
+			rw.mu.Unlock()
+			continue
+		}
+		room = false
+	}
+	return room
+}
+
+// canLoseLocked reports whether the quorum rule can be satisfied by the
+// replicas that have not failed other than rw. w.mu must be held.
+func (w *QuorumWriter) canLoseLocked(rw *quorumReplicaWorker) bool {
+	live := w.replicasLocked(func(other *quorumReplicaWorker) bool {
+		return other != rw && other.failure() == nil
+	})
+	return w.rule(live, w.replicasLocked(nil))
+}
+
+// queueDrained wakes writes waiting for room in the replicas' queues after a
+// worker removed requests from its queue.
+func (w *QuorumWriter) queueDrained() {
+	if w.maxQueueBytes == 0 && w.maxQueueEntries == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cond.Broadcast()
+}
+
+// waitForQuorum waits until a quorum of replicas are durable up to end, or
+// until so many replicas have failed short of end that quorum is impossible.
+// Replicas that are reconnecting have not failed; waiting for them is bounded
+// by ctx.
+func (w *QuorumWriter) waitForQuorum(ctx context.Context, end int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.waitLocked(ctx, func() (bool, error) {
+		if w.committed >= end {
+			return true, nil
+		}
+		// Quorum is still possible as long as the replicas that have not
+		// failed short of end can satisfy the rule.
+		var lastErr error
+		live := w.replicasLocked(func(rw *quorumReplicaWorker) bool {
+			if err := rw.failure(); rw.durable < end && err != nil {
+				lastErr = err
+				return false
+			}
+			return true
+		})
+		if !w.rule(live, w.replicasLocked(nil)) {
+			if lastErr == nil {
+				lastErr = errQuorumUnsatisfiable
+			}
+			return false, lastErr
+		}
+		return false, nil
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil && err == ctxErr {
+		return errors.Wrapf(err, "waiting for quorum at offset %d", end)
+	}
+	return err
+}
+
+// errQuorumUnsatisfiable is returned for writes when the quorum rule cannot be
+// satisfied even by every replica.
+var errQuorumUnsatisfiable = errors.New("quorum rule cannot be satisfied by the replicas")
+
+// replicasLocked returns the replicas whose workers match filter, or all
+// replicas if filter is nil. w.mu must be held.
+func (w *QuorumWriter) replicasLocked(
+	filter func(*quorumReplicaWorker) bool,
+) []basaltpb.ReplicaInfo {
+	replicas := make([]basaltpb.ReplicaInfo, 0, len(w.workers))
+	for _, rw := range w.workers {
+		if filter == nil || filter(rw) {
+			replicas = append(replicas, rw.replica)
+		}
+	}
+	return replicas
+}
+
+// advanceCommittedLocked advances w.committed to the largest durable offset
+// of any replica at which the replicas durable up to that offset satisfy the
+// quorum rule. w.mu must be held.
+func (w *QuorumWriter) advanceCommittedLocked() {
+	all := w.replicasLocked(nil)
+	for _, candidate := range w.workers {
+		end := candidate.durable
+		if end <= w.committed {
+			continue
+		}
+		acked := w.replicasLocked(func(rw *quorumReplicaWorker) bool {
+			return rw.durable >= end
+		})
+		if w.rule(acked, all) {
+			w.committed = end
+		}
+	}
+}
+
+// waitLocked blocks until check reports done or returns an error, evaluating
+// it again each time replica progress changes. It returns ErrClosed if the
+// writer is closed, or ctx.Err() if ctx is done, before check is satisfied.
+// w.mu must be held, and is held while check runs.
+func (w *QuorumWriter) waitLocked(ctx context.Context, check func() (bool, error)) error {
+	stop := context.AfterFunc(ctx, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.cond.Broadcast()
+	})
+	defer stop()
+
+	for {
+		if done, err := check(); done || err != nil {
+			return err
+		}
+		if w.closed {
+			return ErrClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		w.cond.Wait()
+	}
+}
+
+// ReplicaStatus describes the progress of a single replica of a QuorumWriter.
+type ReplicaStatus struct {
+	// Addr is the address of the blob server holding the replica.
+	Addr string
+	// WrittenOffset is the end offset of the prefix of the object that the
+	// replica has written, whether or not it has been synced.
+	WrittenOffset int64
+	// DurableOffset is the end offset of the prefix of the object that the
+	// replica has written and synced.
+	DurableOffset int64
+	// Err is the error that caused the replica to stop accepting writes, or
+	// nil if the replica is healthy.
+	Err error
+	// Ejected reports whether the writer has given up on the replica, in
+	// which case it receives no further writes.
+	Ejected bool
+}
 
-	// Wait for quorum.
-	return w.waitForQuorum(len(workers))
+// ReplicaStatus returns the current progress of each replica, in the order
+// the replicas were passed to NewQuorumWriter. A replica added by
+// ReplaceReplica takes the place of the replica it replaced.
+func (w *QuorumWriter) ReplicaStatus() []ReplicaStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status := make([]ReplicaStatus, len(w.workers))
+	for i, worker := range w.workers {
+		worker.mu.Lock()
+		ejected := worker.ejected
+		worker.mu.Unlock()
+		status[i] = ReplicaStatus{
+			Addr:          worker.replica.Addr,
+			WrittenOffset: worker.written,
+			DurableOffset: worker.durable,
+			Err:           worker.err,
+			Ejected:       ejected,
+		}
+	}
+	return status
 }
 
-// prepareWrite prepares for a write operation, returning the offset and workers.
-// Returns ErrClosed if the writer is closed.
-func (w *QuorumWriter) prepareWrite(data []byte) (int64, []*quorumReplicaWorker, error) {
+// Offset returns the end offset of all writes issued so far.
+func (w *QuorumWriter) Offset() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.offset
+}
+
+// WaitForAllReplicas blocks until every replica, not just a quorum, is
+// durable up to offset. It returns an error if a replica fails short of
+// offset, ErrClosed if the writer is closed first, or ctx.Err() if ctx is
+// done first. Replicas that are reconnecting after a connection failure are
+// waited for.
+func (w *QuorumWriter) WaitForAllReplicas(ctx context.Context, offset int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.waitLocked(ctx, func() (bool, error) {
+		caughtUp := true
+		for _, worker := range w.workers {
+			if worker.durable >= offset {
+				continue
+			}
+			if err := worker.failure(); err != nil {
+				return false, errors.Wrapf(err, "replica %s", worker.replica.Addr)
+			}
+			caughtUp = false
+		}
+		return caughtUp, nil
+	})
+}
+
+// quorumBackfillChunkSize is the size of the reads used to copy data to a
+// replacement replica.
+const quorumBackfillChunkSize = 1 << 20 // 1 MiB
+
+// ReplaceReplica replaces the replica at oldAddr, typically one that has
+// failed permanently, with a new replica. The object is created on the new
+// blob server, the data written so far is copied to it from a healthy
+// replica, and it then joins the fan-out, restoring the writer's replication
+// factor without rolling over to a new object.
+//
+// Writes issued while the new replica is being backfilled are queued for it
+// and sent once the backfill completes; they do not wait for it. The writer
+// must have been configured with WithQuorumBlobControl. Recording the new
+// replica with the controller is the caller's responsibility.
+func (w *QuorumWriter) ReplaceReplica(
+	ctx context.Context, oldAddr string, replacement basaltpb.ReplicaInfo,
+) error {
+	if w.control == nil {
+		return errors.New("replacing a replica requires WithQuorumBlobControl")
+	}
+	if w.chain != nil {
+		return errors.New("replacing a replica is not supported in chain replication mode")
+	}
+	w.replaceMu.Lock()
+	defer w.replaceMu.Unlock()
+
+	ctrl, err := w.control(replacement.Addr)
+	if err != nil {
+		return errors.Wrapf(err, "resolving control client for %s", replacement.Addr)
+	}
+	if err := ctrl.Create(ctx, w.objectID); err != nil {
+		return errors.Wrapf(err, "creating object on %s", replacement.Addr)
+	}
+
+	// Swap in the new worker. From here on, writes are queued to it, so the
+	// backfill only needs to cover everything before end.
+	worker := w.newWorker(replacement)
+	old, end, err := w.swapWorker(oldAddr, worker)
+	if err != nil {
+		_ = worker.client.Close()
+		return err
+	}
+	// The old replica may have stopped responding; abort its request in
+	// flight so that its worker exits.
+	w.eject(old, errReplicaReplaced)
+	old.close()
+	old.client.abort()
+	old.waitForExit()
+
+	if err := w.backfill(ctx, worker, end); err != nil {
+		w.eject(worker, err)
+		_ = worker.client.Close()
+		return errors.Wrapf(err, "backfilling %s", replacement.Addr)
+	}
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	if w.closed {
-		return 0, nil, ErrClosed
+		_ = worker.client.Close()
+		return ErrClosed
 	}
-	offset := w.offset
-	w.offset += int64(len(data))
-	w.currentOffset = offset // track current request for filtering late results
-	w.successCount = 0
-	w.failureCount = 0
-	w.lastError = nil
-	return offset, w.workers, nil
+	worker.written = end
+	worker.durable = end
+	w.advanceCommittedLocked()
+	w.cond.Broadcast()
+	worker.startLocked()
+	return nil
 }
 
-// waitForQuorum waits until quorum is reached or failure threshold is exceeded.
-func (w *QuorumWriter) waitForQuorum(numWorkers int) error {
+// swapWorker replaces the worker for oldAddr with worker, returning the old
+// worker and the end offset of the writes queued to it.
+func (w *QuorumWriter) swapWorker(
+	oldAddr string, worker *quorumReplicaWorker,
+) (*quorumReplicaWorker, int64, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	for w.successCount < w.quorum && w.failureCount <= numWorkers-w.quorum {
-		w.cond.Wait()
+	if w.closed {
+		return nil, 0, ErrClosed
+	}
+	if w.sealing {
+		return nil, 0, ErrSealed
 	}
-	if w.successCount >= w.quorum {
+	for i, old := range w.workers {
+		if old.replica.Addr == oldAddr {
+			// Copy on write, since Close may be iterating the old slice.
+			workers := append([]*quorumReplicaWorker(nil), w.workers...)
+			workers[i] = worker
+			w.workers = workers
+			return old, w.offset, nil
+		}
+	}
+	return nil, 0, errors.Newf("replica %s not found", oldAddr)
+}
+
+// backfill copies the first end bytes of the object from a healthy replica
+// to worker's replica, waiting for a replica to become durable up to end if
+// necessary.
+func (w *QuorumWriter) backfill(ctx context.Context, worker *quorumReplicaWorker, end int64) error {
+	if end == 0 {
 		return nil
 	}
-	return w.lastError
+	source, err := w.waitForSource(ctx, worker, end)
+	if err != nil {
+		return err
+	}
+	src := w.factory(source)
+	defer func() { _ = src.Close() }()
+
+	buf := make([]byte, min(end, quorumBackfillChunkSize))
+	for offset := int64(0); offset < end; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunk := buf[:min(end-offset, int64(len(buf)))]
+		n, err := src.Read(w.objectID, uint64(offset), chunk)
+		if err != nil {
+			return errors.Wrapf(err, "reading from %s", source)
+		}
+		if n == 0 {
+			return errors.Newf("unexpected end of data from %s at offset %d", source, offset)
+		}
+		// Sync only with the final chunk.
+		if offset+int64(n) < end {
+			err = worker.client.Append(w.objectID, uint64(offset), chunk[:n])
+		} else {
+			err = worker.client.AppendSync(w.objectID, uint64(offset), chunk[:n])
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(n)
+	}
+	return nil
 }
 
-// reportResult is called by workers when they complete a request.
-// The offset parameter identifies which request this result belongs to.
-// Results from previous requests (late arrivals) are ignored.
-func (w *QuorumWriter) reportResult(offset uint64, err error) {
+// waitForSource waits for a replica other than worker to become durable up
+// to end, and returns its address.
+func (w *QuorumWriter) waitForSource(
+	ctx context.Context, worker *quorumReplicaWorker, end int64,
+) (string, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	// Ignore late results from previous requests.
-	if int64(offset) != w.currentOffset {
+	var source string
+	err := w.waitLocked(ctx, func() (bool, error) {
+		healthy := false
+		for _, rw := range w.workers {
+			if rw == worker {
+				continue
+			}
+			if rw.durable >= end {
+				source = rw.replica.Addr
+				return true, nil
+			}
+			healthy = healthy || rw.err == nil
+		}
+		if !healthy {
+			return false, errors.New("no healthy replica to backfill from")
+		}
+		return false, nil
+	})
+	return source, err
+}
+
+// SealResult describes the outcome of QuorumWriter.Seal.
+type SealResult struct {
+	// Size is the final size of the object, as recorded with the controller.
+	Size int64
+	// Divergent maps the address of each replica that could not be sealed at
+	// Size to the reason. Such replicas are left as they are; their copies
+	// must not be read and should be repaired or replaced.
+	Divergent map[string]error
+}
+
+// Seal finishes the object: it stops accepting writes, waits for outstanding
+// writes to become durable on a quorum, seals every reachable replica,
+// verifies that a quorum of them agree on the final size, and records that
+// size with the controller. The writer is closed when Seal returns, whether
+// or not Seal succeeded.
+//
+// The final size is the end offset of all writes issued, so every write that
+// WriteAndSync acknowledged, and every write that was still in flight, is
+// part of the sealed object. Healthy replicas are given until ctx is done to
+// catch up before being sealed; replicas that are failing are sealed as they
+// are and reported in SealResult.Divergent if they fall short.
+//
+// The writer must have been configured with WithQuorumBlobControl and
+// WithQuorumController. If Seal returns an error the object has not been
+// recorded as sealed, and the caller must not assume any particular state of
+// the replicas.
+func (w *QuorumWriter) Seal(ctx context.Context) (SealResult, error) {
+	defer func() { _ = w.Close() }()
+	if w.control == nil || w.controller == nil {
+		return SealResult{}, errors.New(
+			"sealing requires WithQuorumBlobControl and WithQuorumController")
+	}
+	w.replaceMu.Lock()
+	defer w.replaceMu.Unlock()
+
+	end, err := w.startSeal()
+	if err != nil {
+		return SealResult{}, err
+	}
+	if err := w.waitForQuorum(ctx, end); err != nil {
+		return SealResult{}, errors.Wrap(err, "draining writes")
+	}
+	if err := w.waitForHealthyReplicas(ctx, end); err != nil {
+		return SealResult{}, errors.Wrap(err, "draining writes")
+	}
+
+	// Stop the workers before sealing so that no further appends race with
+	// the seals.
+	w.mu.Lock()
+	workers := w.workers
+	w.mu.Unlock()
+	_ = w.Close()
+
+	sizes := make([]int64, len(workers))
+	errs := make([]error, len(workers))
+	var wg sync.WaitGroup
+	for i, rw := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctrl, err := w.control(rw.replica.Addr)
+			if err != nil {
+				errs[i] = errors.Wrapf(err, "resolving control client for %s", rw.replica.Addr)
+				return
+			}
+			sizes[i], errs[i] = ctrl.Seal(ctx, w.objectID)
+		}()
+	}
+	wg.Wait()
+
+	result := SealResult{Size: end}
+	var agreed, all []basaltpb.ReplicaInfo
+	for i, rw := range workers {
+		all = append(all, rw.replica)
+		switch {
+		case errs[i] != nil:
+			result.Divergent = addDivergent(result.Divergent, rw.replica.Addr, errs[i])
+		case sizes[i] != end:
+			result.Divergent = addDivergent(result.Divergent, rw.replica.Addr,
+				errors.Newf("sealed at size %d, expected %d", sizes[i], end))
+		default:
+			agreed = append(agreed, rw.replica)
+		}
+	}
+	if !w.rule(agreed, all) {
+		return result, errors.Newf(
+			"only %d of %d replicas sealed at size %d, which is not a quorum",
+			len(agreed), len(workers), end)
+	}
+
+	if err := w.controller.Seal(ctx, w.objectID[:], end); err != nil {
+		return result, errors.Wrap(err, "recording sealed size")
+	}
+	return result, nil
+}
+
+func addDivergent(m map[string]error, addr string, err error) map[string]error {
+	if m == nil {
+		m = make(map[string]error)
+	}
+	m[addr] = err
+	return m
+}
+
+// startSeal stops the writer from accepting writes, asks the replicas to sync
+// everything written, and returns the final size of the object.
+func (w *QuorumWriter) startSeal() (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if w.sealing {
+		return 0, ErrSealed
+	}
+	w.sealing = true
+	for _, worker := range w.sendersLocked() {
+		worker.requestSync(w.offset)
+	}
+	return w.offset, nil
+}
+
+// waitForHealthyReplicas waits until every replica that has not failed is
+// durable up to end. Unlike WaitForAllReplicas, replicas that have failed
+// are skipped rather than waited for; replicas that are reconnecting are
+// waited for.
+func (w *QuorumWriter) waitForHealthyReplicas(ctx context.Context, end int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.waitLocked(ctx, func() (bool, error) {
+		for _, worker := range w.workers {
+			if worker.durable < end && worker.failure() == nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// reportResult is called by workers when they complete a request covering
+// the range [start, end), which is synced along with everything before it if
+// sync is set. A replica's offsets only advance over contiguous successful
+// requests. Results reported after the writer is closed, such as the failures
+// of the requests Close aborted, are ignored.
+func (w *QuorumWriter) reportResult(
+	rw *quorumReplicaWorker, start, end int64, sync bool, err error,
+) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	rw.mu.Lock()
+	ejected := rw.ejected
+	rw.mu.Unlock()
+	if ejected {
 		return
 	}
-	if err == nil {
-		w.successCount++
-	} else {
-		w.failureCount++
-		w.lastError = err
+	workers := []*quorumReplicaWorker{rw}
+	if rw == w.chain {
+		// An acknowledgement from the chain covers every replica left in it,
+		// while a failure is a failure of the first replica.
+		members := w.chainMembersLocked()
+		if err != nil {
+			members = members[:min(len(members), 1)]
+		}
+		workers = append(workers, members...)
+	}
+	for _, rw := range workers {
+		if err != nil {
+			rw.err = err
+		} else if start == rw.written {
+			rw.written = end
+			rw.err = nil
+			if sync {
+				rw.durable = end
+			}
+		}
+	}
+	if err == nil && sync {
+		w.advanceCommittedLocked()
+	}
+	w.cond.Broadcast()
+}
+
+// chainMembersLocked returns the workers of the replicas left in the chain,
+// in chain order. w.mu must be held.
+func (w *QuorumWriter) chainMembersLocked() []*quorumReplicaWorker {
+	members := make([]*quorumReplicaWorker, 0, len(w.workers))
+	for _, rw := range w.workers {
+		if !rw.inChain {
+			continue
+		}
+		rw.mu.Lock()
+		ejected := rw.ejected
+		rw.mu.Unlock()
+		if !ejected {
+			members = append(members, rw)
+		}
+	}
+	return members
+}
+
+// eject gives up on a replica: its queued requests are released, later
+// requests are dropped, and err is recorded as its final error.
+func (w *QuorumWriter) eject(rw *quorumReplicaWorker, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if rw == w.chain {
+		// The chain cannot make further progress, so neither can any of the
+		// replicas left in it.
+		for _, member := range w.chainMembersLocked() {
+			member.mu.Lock()
+			member.ejectLocked(err)
+			member.mu.Unlock()
+		}
 	}
-	w.cond.Signal()
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.ejectLocked(err)
 }
 
-// Close closes the quorum writer and all worker goroutines.
+// Close closes the quorum writer and all worker goroutines. Requests in flight
+// to the replicas are aborted, and Close returns once the workers have exited.
 func (w *QuorumWriter) Close() error {
 	workers, alreadyClosed := w.markClosed()
 	if alreadyClosed {
 		return nil
 	}
 
-	// Signal all workers to stop.
+	// Signal all workers to stop, failing any requests they are blocked on.
 	for _, worker := range workers {
 		worker.close()
+		worker.client.abort()
 	}
 
 	// Wait for all workers to exit.
@@ -199,75 +1267,362 @@ func (w *QuorumWriter) markClosed() ([]*quorumReplicaWorker, bool) {
 		return nil, true
 	}
 	w.closed = true
+	// Wake up any writes waiting for quorum.
+	w.cond.Broadcast()
+	if w.chain != nil {
+		return append([]*quorumReplicaWorker{w.chain}, w.workers...), false
+	}
 	return w.workers, false
 }
 
-// waitForExit blocks until the worker has exited.
+// waitForExit blocks until the worker's run goroutine, if it was started, has
+// exited. The worker must no longer be startable, because the writer is
+// closed or the worker has been replaced.
 func (rw *quorumReplicaWorker) waitForExit() {
+	if rw.done != nil {
+		<-rw.done
+	}
+}
+
+// failure returns the error the replica failed with, or nil if it is healthy
+// or only reconnecting after a connection failure, which its worker retries.
+// w.mu must be held.
+func (rw *quorumReplicaWorker) failure() error {
+	rw.mu.Lock()
+	ejected := rw.ejected
+	rw.mu.Unlock()
+	if rw.err == nil || (!ejected && errors.Is(rw.err, errConnFailed)) {
+		return nil
+	}
+	return rw.err
+}
+
+// requestSync asks the worker to sync the replica up to at least end. w.mu
+// must be held.
+func (rw *quorumReplicaWorker) requestSync(end int64) {
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
-	for !rw.closed {
-		rw.cond.Wait()
+	if end > rw.syncTo {
+		rw.syncTo = end
+		rw.cond.Signal()
 	}
 }
 
-// enqueue adds a request to the worker's queue.
+// enqueue adds a request to the worker's queue. w.mu must be held.
 func (rw *quorumReplicaWorker) enqueue(req quorumRequest) {
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
+	if rw.ejected {
+		return
+	}
 	rw.queue = append(rw.queue, req)
+	rw.queuedBytes += int64(len(req.data))
+	if rw.reconnecting && rw.queuedBytes > rw.w.replayBufferSize {
+		rw.ejectLocked(errReplayBufferExceeded)
+		return
+	}
 	rw.cond.Signal()
 }
 
+// overQueueLimit reports whether queueing a write of n bytes would take the
+// worker over the limit set by WithQuorumReplicaQueueLimit.
+func (rw *quorumReplicaWorker) overQueueLimit(n int) bool {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.ejected {
+		return false
+	}
+	return (rw.w.maxQueueBytes > 0 && rw.queuedBytes+int64(n) > rw.w.maxQueueBytes) ||
+		(rw.w.maxQueueEntries > 0 && len(rw.queue)+1 > rw.w.maxQueueEntries)
+}
+
+// ejectLocked marks the worker as ejected, releases its queue, and notifies
+// the ejection callback and failure reporter. Both w.mu and rw.mu must be held.
+func (rw *quorumReplicaWorker) ejectLocked(err error) {
+	if rw.ejected {
+		return
+	}
+	rw.ejected = true
+	rw.queue = nil
+	rw.queuedBytes = 0
+	rw.err = err
+	if err == errReplicaReplaced {
+		return
+	}
+	// The chain worker is not a replica; the replicas it fails are ejected
+	// individually.
+	if rw != rw.w.chain {
+		if rw.w.onEject != nil {
+			go rw.w.onEject(rw.replica.Addr, err)
+		}
+		if rw.w.reporter != nil {
+			rw.w.reporter.Report(rw.w.objectID, rw.replica.Addr, err)
+		}
+	}
+	rw.cond.Broadcast()
+	rw.w.cond.Broadcast()
+}
+
 // close signals the worker to stop processing.
 func (rw *quorumReplicaWorker) close() {
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
 	rw.closed = true
-	rw.cond.Signal()
+	rw.cond.Broadcast()
+}
+
+// startLocked starts the worker's run goroutine. w.mu must be held.
+func (rw *quorumReplicaWorker) startLocked() {
+	rw.done = make(chan struct{})
+	go rw.run(rw.w.objectID)
 }
 
 // run is the worker goroutine that processes requests.
 func (rw *quorumReplicaWorker) run(objectID ObjectID) {
 	defer func() {
 		_ = rw.client.Close()
-		// Signal that we've exited.
-		rw.mu.Lock()
-		rw.closed = true
-		rw.mu.Unlock()
-		rw.cond.Broadcast()
+		close(rw.done)
 	}()
 
+	// The worker is the only one advancing the replica's offsets once it has
+	// started, so it tracks them locally.
+	rw.w.mu.Lock()
+	written, durable := rw.written, rw.durable
+	rw.w.mu.Unlock()
+
+	var backoff time.Duration
+	resync := false
 	for {
-		req, ok := rw.dequeue()
+		if resync {
+			// The connection failed before the replica acknowledged the
+			// queued writes, but it may have applied some of them. Skip
+			// those rather than replay them at an offset the replica would
+			// reject.
+			held, err := rw.resync(objectID, written)
+			if err != nil {
+				rw.w.reportResult(rw, written, written, false, err)
+				if !rw.retry(err, &backoff) {
+					return
+				}
+				continue
+			}
+			resync = false
+			if held > 0 {
+				rw.trim(held)
+				rw.w.queueDrained()
+				rw.w.reportResult(rw, written, written+held, false, nil)
+				written += held
+			}
+		}
+
+		b, ok := rw.next(written, durable)
 		if !ok {
-			return // Closed
+			return // Closed or ejected
+		}
+
+		var err error
+		if b.sync && len(b.data) == 0 {
+			// The offset of an empty append is ignored; send 0 as
+			// BlobDataClient.Sync does.
+			err = rw.client.AppendSync(objectID, 0, nil)
+		} else if b.sync {
+			err = rw.client.AppendSync(objectID, uint64(b.offset), b.data)
+		} else {
+			err = rw.client.Append(objectID, uint64(b.offset), b.data)
 		}
+		end := b.offset + int64(len(b.data))
+		rw.w.reportResult(rw, b.offset, end, b.sync, err)
+		if err == nil {
+			rw.pop(b.n)
+			rw.w.queueDrained()
+			written = end
+			if b.sync {
+				durable = end
+			}
+			backoff = 0
+			continue
+		}
+		if !rw.retry(err, &backoff) {
+			return
+		}
+		resync = true
+	}
+}
+
+// retry handles a failed request. Connection errors are retried after
+// backing off; other errors (e.g. the object is sealed, or the replica
+// rejected the offset) are not fixed by reconnecting and eject the replica.
+// Returns false if the worker should exit.
+func (rw *quorumReplicaWorker) retry(err error, backoff *time.Duration) bool {
+	if !errors.Is(err, errConnFailed) {
+		rw.w.eject(rw, err)
+		return false
+	}
+	if !rw.startReconnect() {
+		rw.w.eject(rw, errReplayBufferExceeded)
+		return false
+	}
+	// Drop the connection so that the next attempt reconnects.
+	_ = rw.client.Close()
+	*backoff = min(max(2**backoff, rw.w.minBackoff), rw.w.maxBackoff)
+	return rw.sleep(*backoff)
+}
 
-		// Append + Sync in a single round-trip.
-		err := rw.client.AppendSync(objectID, req.offset, req.data)
+// resync determines how much of the queue, which starts at offset written,
+// the replica already holds. It reads the replica's data back from written
+// and compares it with the queued writes, stopping at the end of the
+// replica's data. A replica holding different data fails with
+// ErrDivergentReplicas. The chain worker reconciles the replicas left in the
+// chain instead; see chainClient.resync.
+func (rw *quorumReplicaWorker) resync(objectID ObjectID, written int64) (int64, error) {
+	// Only the run goroutine removes requests from the queue, so the
+	// requests seen here stay valid while they are compared.
+	rw.mu.Lock()
+	queue := rw.queue
+	rw.mu.Unlock()
+	if c, ok := rw.client.(*chainClient); ok {
+		return c.resync(objectID, written, queue)
+	}
+	return heldPrefix(rw.client, objectID, written, queue, rw.replica.Addr)
+}
 
-		// Report result to the writer, including offset to filter late results.
-		rw.w.reportResult(req.offset, err)
+// heldPrefix returns how many bytes of the queued writes, which start at
+// offset, the replica behind client holds.
+func heldPrefix(
+	client quorumClient, objectID ObjectID, offset int64, queue []quorumRequest, addr string,
+) (int64, error) {
+	var held int64
+	var buf []byte
+	for _, req := range queue {
+		for data := req.data; len(data) > 0; {
+			chunk := data[:min(len(data), quorumCoalesceLimit)]
+			if cap(buf) < len(chunk) {
+				buf = make([]byte, quorumCoalesceLimit)
+			}
+			n, err := client.Read(objectID, uint64(offset+held), buf[:len(chunk)])
+			if err != nil {
+				return 0, errors.Wrapf(err, "reading back %s at offset %d", addr, offset+held)
+			}
+			if !bytes.Equal(buf[:n], chunk[:n]) {
+				return 0, errors.Wrapf(ErrDivergentReplicas,
+					"%s holds different data than written at offset %d", addr, offset+held)
+			}
+			held += int64(n)
+			if n < len(chunk) {
+				return held, nil
+			}
+			data = data[n:]
+		}
 	}
+	return held, nil
 }
 
-// dequeue waits for and returns the next request.
-func (rw *quorumReplicaWorker) dequeue() (quorumRequest, bool) {
+// next waits for and returns the next request to send to the replica, given
+// the replica's written and durable offsets. Queued writes are coalesced into
+// a single request, which also syncs the replica if a sync is pending that it
+// covers; a pending sync with nothing queued is sent without data. Requests
+// stay queued until they are acknowledged. Returns false if the worker has
+// been ejected, or closed with nothing left to write.
+func (rw *quorumReplicaWorker) next(written, durable int64) (quorumBatch, bool) {
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
 
-	for len(rw.queue) == 0 && !rw.closed {
+	for len(rw.queue) == 0 && rw.syncTo <= durable && !rw.closed && !rw.ejected {
 		rw.cond.Wait()
 	}
 
+	if rw.ejected {
+		return quorumBatch{}, false
+	}
 	if len(rw.queue) == 0 {
-		return quorumRequest{}, false
+		if rw.syncTo > durable && !rw.closed {
+			return quorumBatch{offset: written, sync: true}, true
+		}
+		return quorumBatch{}, false
+	}
+
+	b := quorumBatch{offset: int64(rw.queue[0].offset), n: 1, data: rw.queue[0].data}
+	size := len(b.data)
+	for b.n < len(rw.queue) && size+len(rw.queue[b.n].data) <= quorumCoalesceLimit {
+		size += len(rw.queue[b.n].data)
+		b.n++
+	}
+	if b.n > 1 {
+		rw.scratch = rw.scratch[:0]
+		for _, req := range rw.queue[:b.n] {
+			rw.scratch = append(rw.scratch, req.data...)
+		}
+		b.data = rw.scratch
+	}
+	end := b.offset + int64(size)
+	b.sync = rw.syncTo > durable && (b.n == len(rw.queue) || rw.syncTo <= end)
+	return b, true
+}
+
+// pop removes the first n requests, which the replica has acknowledged, from
+// the head of the queue.
+func (rw *quorumReplicaWorker) pop(n int) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.ejected {
+		return
+	}
+	for i := range rw.queue[:n] {
+		rw.queuedBytes -= int64(len(rw.queue[i].data))
+		rw.queue[i] = quorumRequest{} // release the data
+	}
+	rw.queue = rw.queue[n:]
+	rw.reconnecting = false
+}
+
+// trim removes the first n bytes, which the replica turned out to hold
+// already, from the head of the queue.
+func (rw *quorumReplicaWorker) trim(n int64) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.ejected {
+		return
+	}
+	for n > 0 && len(rw.queue) > 0 {
+		req := &rw.queue[0]
+		k := min(n, int64(len(req.data)))
+		req.offset += uint64(k)
+		req.data = req.data[k:]
+		rw.queuedBytes -= k
+		n -= k
+		if len(req.data) == 0 {
+			rw.queue[0] = quorumRequest{} // release the data
+			rw.queue = rw.queue[1:]
+		}
 	}
+}
+
+// startReconnect marks the worker as reconnecting. Returns false if the
+// worker has already fallen too far behind to replay its queue.
+func (rw *quorumReplicaWorker) startReconnect() bool {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.reconnecting = true
+	return rw.queuedBytes <= rw.w.replayBufferSize
+}
+
+// sleep waits for d to elapse. Returns false if the worker was closed or
+// ejected in the meantime.
+func (rw *quorumReplicaWorker) sleep(d time.Duration) bool {
+	deadline := time.Now().Add(d)
+	t := time.AfterFunc(d, func() {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+		rw.cond.Broadcast()
+	})
+	defer t.Stop()
 
-	req := rw.queue[0]
-	rw.queue = rw.queue[1:]
-	return req, true
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	for !rw.closed && !rw.ejected && time.Now().Before(deadline) {
+		rw.cond.Wait()
+	}
+	return !rw.closed && !rw.ejected
 }
 
 // ErrClosed is returned when operating on a closed quorum writer.
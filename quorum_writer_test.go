@@ -1,12 +1,15 @@
 package basaltclient
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/basaltclient/basaltpb"
+	"github.com/cockroachdb/errors"
 )
 
 // mockQuorumClient is a test double for quorumClient that allows precise control
@@ -15,30 +18,84 @@ type mockQuorumClient struct {
 	mu              sync.Mutex
 	appendSyncStart chan struct{} // closed when AppendSync is called
 	appendSyncDone  chan error    // send error (or nil) to complete AppendSync
+	aborted         chan struct{} // closed by abort
+	abortOnce       sync.Once
 	startClosed     bool
+	closed          bool
+	calls           []mockCall
+}
+
+// mockCall records a request made to a mockQuorumClient.
+type mockCall struct {
+	sync   bool
+	offset uint64
+	data   string
 }
 
 func newMockQuorumClient() *mockQuorumClient {
 	return &mockQuorumClient{
 		appendSyncStart: make(chan struct{}),
 		appendSyncDone:  make(chan error),
+		aborted:         make(chan struct{}),
 	}
 }
 
 func (m *mockQuorumClient) AppendSync(id ObjectID, offset uint64, data []byte) error {
+	return m.do(mockCall{sync: true, offset: offset, data: string(data)})
+}
+
+func (m *mockQuorumClient) Append(id ObjectID, offset uint64, data []byte) error {
+	return m.do(mockCall{offset: offset, data: string(data)})
+}
+
+func (m *mockQuorumClient) AppendChain(
+	id ObjectID, offset uint64, next []string, data []byte, sync bool,
+) error {
+	return errors.New("mockQuorumClient does not support chained appends")
+}
+
+func (m *mockQuorumClient) do(call mockCall) error {
 	m.mu.Lock()
+	m.calls = append(m.calls, call)
 	if !m.startClosed {
 		m.startClosed = true
 		close(m.appendSyncStart)
 	}
+	done := m.appendSyncDone
 	m.mu.Unlock()
-	return <-m.appendSyncDone
+	select {
+	case err := <-done:
+		return err
+	case <-m.aborted:
+		return errors.Mark(errClientAborted, errConnFailed)
+	}
+}
+
+// Read implements quorumClient. The mock holds no data, so a replica reading
+// back its writes after a reconnect finds none of them.
+func (m *mockQuorumClient) Read(id ObjectID, offset uint64, p []byte) (int, error) {
+	return 0, nil
+}
+
+func (m *mockQuorumClient) SetRequestTimeout(d time.Duration) {}
+
+func (m *mockQuorumClient) abort() {
+	m.abortOnce.Do(func() { close(m.aborted) })
 }
 
 func (m *mockQuorumClient) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
 	return nil
 }
 
+func (m *mockQuorumClient) isClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
 // waitForStart blocks until AppendSync has been called on this client.
 func (m *mockQuorumClient) waitForStart(t *testing.T) {
 	t.Helper()
@@ -49,9 +106,20 @@ func (m *mockQuorumClient) waitForStart(t *testing.T) {
 	}
 }
 
-// complete signals AppendSync to return with the given error.
+// recordedCalls returns the requests made so far.
+func (m *mockQuorumClient) recordedCalls() []mockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mockCall(nil), m.calls...)
+}
+
+// complete signals AppendSync to return with the given error. It does nothing
+// once the client has been aborted.
 func (m *mockQuorumClient) complete(err error) {
-	m.appendSyncDone <- err
+	select {
+	case m.appendSyncDone <- err:
+	case <-m.aborted:
+	}
 }
 
 // reset prepares the client for another request.
@@ -109,7 +177,7 @@ func TestQuorumWriterLateResult(t *testing.T) {
 	wg1.Add(1)
 	go func() {
 		defer wg1.Done()
-		req1Err = w.WriteAndSync([]byte("request1"))
+		req1Err = w.WriteAndSync(context.Background(), []byte("request1"))
 		req1Done.Store(true)
 	}()
 
@@ -140,7 +208,7 @@ func TestQuorumWriterLateResult(t *testing.T) {
 	wg2.Add(1)
 	go func() {
 		defer wg2.Done()
-		req2Err = w.WriteAndSync([]byte("request2"))
+		req2Err = w.WriteAndSync(context.Background(), []byte("request2"))
 		req2Done.Store(true)
 	}()
 
@@ -191,3 +259,1421 @@ func TestQuorumWriterLateResult(t *testing.T) {
 	// Note: Worker 2 may still be blocked waiting for request 2 completion.
 	// The defer w.Close() will clean up all workers.
 }
+
+// newTestQuorumWriter creates a quorum writer backed by n mock clients.
+func newTestQuorumWriter(
+	t *testing.T, n int, opts ...QuorumWriterOption,
+) (*QuorumWriter, []*mockQuorumClient) {
+	t.Helper()
+	replicas := make([]basaltpb.ReplicaInfo, n)
+	for i := range replicas {
+		replicas[i] = basaltpb.ReplicaInfo{Addr: fmt.Sprintf("addr%d", i)}
+	}
+	return newTestQuorumWriterWithReplicas(t, replicas, opts...)
+}
+
+// newTestQuorumWriterWithReplicas is like newTestQuorumWriter, but for the
+// given replicas.
+func newTestQuorumWriterWithReplicas(
+	t *testing.T, replicas []basaltpb.ReplicaInfo, opts ...QuorumWriterOption,
+) (*QuorumWriter, []*mockQuorumClient) {
+	t.Helper()
+	clients := make([]*mockQuorumClient, len(replicas))
+	for i := range clients {
+		clients[i] = newMockQuorumClient()
+	}
+	clientIdx := 0
+	factory := func(addr string) quorumClient {
+		c := clients[clientIdx]
+		clientIdx++
+		return c
+	}
+	w := newQuorumWriterWithFactory(ObjectID{1}, replicas, factory, opts...)
+	t.Cleanup(func() { _ = w.Close() })
+	return w, clients
+}
+
+// asyncWrite starts a WriteAndSync in the background and returns a channel
+// that receives its result.
+func asyncWrite(w *QuorumWriter, data []byte) chan error {
+	ch := make(chan error, 1)
+	go func() { ch <- w.WriteAndSync(context.Background(), data) }()
+	return ch
+}
+
+// waitForOffset waits until the writer has assigned offsets up to offset,
+// i.e. all writes before that point have been queued to the replicas.
+func waitForOffset(t *testing.T, w *QuorumWriter, offset int64) {
+	t.Helper()
+	waitFor(t, func() error {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if got := w.offset; got != offset {
+			return errors.Newf("timeout waiting for offset %d (at %d)", offset, got)
+		}
+		return nil
+	})
+}
+
+// waitForDurable waits until replica i of the writer is durable up to
+// offset.
+func waitForDurable(t *testing.T, w *QuorumWriter, i int, offset int64) {
+	t.Helper()
+	waitFor(t, func() error {
+		if s := w.ReplicaStatus()[i]; s.DurableOffset < offset {
+			return errors.Newf("timeout waiting for replica %d to be durable up to %d (%+v)", i, offset, s)
+		}
+		return nil
+	})
+}
+
+// expectPending verifies that a write has not completed yet.
+func expectPending(t *testing.T, ch chan error) {
+	t.Helper()
+	select {
+	case err := <-ch:
+		t.Fatalf("write completed early: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// expectResult waits for a write to complete with the given error.
+func expectResult(t *testing.T, ch chan error, want error) {
+	t.Helper()
+	select {
+	case err := <-ch:
+		if !errors.Is(err, want) {
+			t.Fatalf("expected %v, got %v", want, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for write to complete")
+	}
+}
+
+// TestQuorumWriterPipelined tests that multiple writes can be in flight and
+// that each completes only once a quorum has acknowledged it and all prior
+// writes.
+func TestQuorumWriterPipelined(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3)
+
+	reqA := asyncWrite(w, []byte("aaaa"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	reqB := asyncWrite(w, []byte("bbbb"))
+	waitForOffset(t, w, 8)
+
+	// Replica 0 acknowledges both writes, replica 1 only the first.
+	clients[0].complete(nil)
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	expectResult(t, reqA, nil)
+	expectPending(t, reqB)
+
+	// Replica 2 acknowledges both, giving B a quorum.
+	clients[2].complete(nil)
+	clients[2].complete(nil)
+	expectResult(t, reqB, nil)
+
+	clients[1].complete(nil)
+}
+
+// TestQuorumWriterFailedReplicaNotCounted tests that a replica that failed an
+// earlier write does not count towards quorum for later writes.
+func TestQuorumWriterFailedReplicaNotCounted(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3)
+	errReplica := errors.New("replica failed")
+
+	reqA := asyncWrite(w, []byte("aaaa"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	clients[2].complete(errReplica)
+	expectResult(t, reqA, nil)
+
+	// Replica 2 is not sent B, so losing replica 1 loses quorum.
+	reqB := asyncWrite(w, []byte("bbbb"))
+	clients[0].complete(nil)
+	clients[1].complete(errReplica)
+	expectResult(t, reqB, errReplica)
+}
+
+// TestQuorumWriterCloseUnblocksWrites tests that closing the writer fails
+// writes that are waiting for quorum.
+func TestQuorumWriterCloseUnblocksWrites(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3)
+
+	req := asyncWrite(w, []byte("data"))
+	clients[0].waitForStart(t)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The workers, which close their clients on exit, have exited even
+	// though their requests never completed.
+	for i, c := range clients {
+		if !c.isClosed() {
+			t.Fatalf("client %d: expected worker to have exited", i)
+		}
+	}
+	expectResult(t, req, ErrClosed)
+	if err := w.WriteAndSync(context.Background(), []byte("more")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	for _, c := range clients {
+		c.complete(nil)
+	}
+}
+
+// TestQuorumWriterReplicaStatus tests per-replica progress tracking and
+// waiting for all replicas to catch up.
+func TestQuorumWriterReplicaStatus(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3)
+	errReplica := errors.New("replica failed")
+
+	req := asyncWrite(w, []byte("data"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	expectResult(t, req, nil)
+
+	status := w.ReplicaStatus()
+	want := []int64{4, 4, 0}
+	for i, s := range status {
+		if s.Addr != fmt.Sprintf("addr%d", i) || s.DurableOffset != want[i] || s.Err != nil {
+			t.Fatalf("unexpected status for replica %d: %+v", i, s)
+		}
+	}
+
+	// Replica 2 is lagging, so waiting for all replicas times out.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := w.WaitForAllReplicas(ctx, w.Offset()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// Once replica 2 fails, waiting returns its error immediately.
+	done := make(chan error, 1)
+	go func() { done <- w.WaitForAllReplicas(context.Background(), w.Offset()) }()
+	clients[2].complete(errReplica)
+	expectResult(t, done, errReplica)
+	if s := w.ReplicaStatus()[2]; s.DurableOffset != 0 || !errors.Is(s.Err, errReplica) {
+		t.Fatalf("unexpected status for replica 2: %+v", s)
+	}
+	if err := w.WaitForAllReplicas(context.Background(), 0); err != nil {
+		t.Fatalf("unexpected error waiting for offset 0: %v", err)
+	}
+}
+
+// TestQuorumWriterReconnect tests that a replica whose connection fails
+// reconnects, replays the writes it missed, and rejoins the quorum.
+func TestQuorumWriterReconnect(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3, WithQuorumReconnectBackoff(time.Millisecond, time.Millisecond))
+	errConn := errors.Mark(errors.New("connection reset"), errConnFailed)
+
+	reqA := asyncWrite(w, []byte("aaaa"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	clients[2].complete(errConn)
+	expectResult(t, reqA, nil)
+
+	// B is queued behind the failed write on replica 2.
+	reqB := asyncWrite(w, []byte("bbbb"))
+	waitForOffset(t, w, 8)
+	if s := w.ReplicaStatus()[2]; !errors.Is(s.Err, errConn) {
+		t.Fatalf("expected replica 2 to report connection error, got %+v", s)
+	}
+
+	// Replica 2 reconnects and replays A and B, coalesced into one request.
+	// Together with replica 0 it forms a quorum for B even though replica 1
+	// fails.
+	clients[2].complete(nil)
+	waitForDurable(t, w, 2, 8)
+	clients[0].complete(nil)
+	clients[1].complete(errors.New("replica failed"))
+	expectResult(t, reqB, nil)
+
+	if s := w.ReplicaStatus()[2]; s.DurableOffset != 8 || s.Err != nil {
+		t.Fatalf("expected replica 2 to have caught up, got %+v", s)
+	}
+}
+
+// TestQuorumWriterReconnectAfterApplied tests that a replica that applied a
+// write whose acknowledgement was lost to a connection failure is not sent
+// the write again, and stays in the quorum.
+func TestQuorumWriterReconnectAfterApplied(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, WithQuorumReconnectBackoff(time.Millisecond, time.Millisecond))
+	ctx := context.Background()
+
+	c.replica("addr2").setDropResponses(1)
+	if err := w.WriteAndSync(ctx, []byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteAndSync(ctx, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	waitForDurable(t, w, 2, 11)
+	for i, status := range w.ReplicaStatus() {
+		if status.DurableOffset != 11 || status.Err != nil {
+			t.Fatalf("replica %d: unexpected status %+v", i, status)
+		}
+		if got := c.replica(status.Addr).contents(); got != "hello world" {
+			t.Fatalf("replica %d: expected %q, got %q", i, "hello world", got)
+		}
+	}
+}
+
+// TestQuorumWriterReconnectNotFailed tests that replicas that are reconnecting
+// after a connection failure are waited for rather than counted as failed,
+// even when too few replicas are left for a quorum without them.
+func TestQuorumWriterReconnectNotFailed(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, WithQuorumReconnectBackoff(time.Millisecond, time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c.replica("addr1").setDropResponses(1)
+	c.replica("addr2").setDropResponses(1)
+	if err := w.WriteAndSync(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	c.replica("addr0").setDropResponses(1)
+	if err := w.WriteAndSync(ctx, []byte(" world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WaitForAllReplicas(ctx, w.Offset()); err != nil {
+		t.Fatal(err)
+	}
+	for i, status := range w.ReplicaStatus() {
+		if status.DurableOffset != 11 || status.Err != nil {
+			t.Fatalf("replica %d: unexpected status %+v", i, status)
+		}
+	}
+}
+
+// TestQuorumWriterReplayBufferExceeded tests that a reconnecting replica that
+// falls too far behind is given up on.
+func TestQuorumWriterReplayBufferExceeded(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3,
+		WithQuorumReconnectBackoff(time.Hour, time.Hour), WithQuorumReplayBufferSize(8))
+	errConn := errors.Mark(errors.New("connection reset"), errConnFailed)
+
+	reqA := asyncWrite(w, []byte("aaaa"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	clients[2].complete(errConn)
+	expectResult(t, reqA, nil)
+
+	// Replica 2 is waiting to reconnect with A still queued. Writing B fits
+	// in the replay buffer, but C does not.
+	for _, data := range []string{"bbbb", "cccc"} {
+		req := asyncWrite(w, []byte(data))
+		clients[0].complete(nil)
+		clients[1].complete(nil)
+		expectResult(t, req, nil)
+	}
+	if s := w.ReplicaStatus()[2]; !errors.Is(s.Err, errReplayBufferExceeded) {
+		t.Fatalf("expected replica 2 to be given up on, got %+v", s)
+	}
+}
+
+// memQuorumClient is a quorumClient that completes requests immediately
+// against an in-memory object.
+type memQuorumClient struct {
+	c      *memCluster // for forwarding chained appends
+	mu     sync.Mutex
+	data   []byte
+	err    error // if set, returned by all requests
+	sealed bool
+	chains int // chained appends received from the client
+	// dropResponses is the number of subsequent appends that are applied but
+	// then fail with a connection error, as if the response was lost. A
+	// chained append whose response is lost is first forwarded to at most
+	// dropForwards of the replicas after this one.
+	dropResponses int
+	dropForwards  int
+	stall         chan struct{} // if set, appends wait for it to be closed
+	aborted       chan struct{} // closed by abort; fails stalled appends
+	abortOnce     sync.Once
+	closed        bool
+}
+
+func (m *memQuorumClient) Append(id ObjectID, offset uint64, data []byte) error {
+	return m.AppendSync(id, offset, data)
+}
+
+func (m *memQuorumClient) AppendSync(id ObjectID, offset uint64, data []byte) error {
+	if err := m.apply(offset, data); err != nil {
+		return err
+	}
+	if m.dropResponse() {
+		return errors.Mark(errors.New("connection reset"), errConnFailed)
+	}
+	return nil
+}
+
+// apply appends data to the replica at offset.
+func (m *memQuorumClient) apply(offset uint64, data []byte) error {
+	m.mu.Lock()
+	stall := m.stall
+	m.mu.Unlock()
+	if stall != nil {
+		select {
+		case <-stall:
+		case <-m.aborted:
+			return errors.Mark(errClientAborted, errConnFailed)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.sealed {
+		return ErrSealed
+	}
+	// Like a blob server, ignore the offset of an empty append.
+	if len(data) > 0 && offset != uint64(len(m.data)) {
+		return ErrBadRequest
+	}
+	m.data = append(m.data, data...)
+	return nil
+}
+
+// dropResponse reports whether the response to an applied append is lost.
+func (m *memQuorumClient) dropResponse() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.dropResponses == 0 {
+		return false
+	}
+	m.dropResponses--
+	return true
+}
+
+// AppendChain appends data and forwards it to the replicas at next in the
+// same cluster, like a blob server would.
+func (m *memQuorumClient) AppendChain(
+	id ObjectID, offset uint64, next []string, data []byte, sync bool,
+) error {
+	m.mu.Lock()
+	m.chains++
+	m.mu.Unlock()
+	if err := m.apply(offset, data); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	forwards := len(next)
+	if m.dropResponses > 0 {
+		forwards = min(forwards, m.dropForwards)
+	}
+	m.mu.Unlock()
+	for i, addr := range next[:forwards] {
+		if err := m.c.replica(addr).apply(offset, data); err != nil {
+			return errors.Mark(&ChainError{Index: i, Addr: addr}, ErrChainFailed)
+		}
+	}
+	if m.dropResponse() {
+		return errors.Mark(errors.New("connection reset"), errConnFailed)
+	}
+	return nil
+}
+
+func (m *memQuorumClient) chainRequests() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.chains
+}
+
+func (m *memQuorumClient) Read(id ObjectID, offset uint64, p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return 0, m.err
+	}
+	if offset > uint64(len(m.data)) {
+		return 0, ErrBadRequest
+	}
+	return copy(p, m.data[offset:]), nil
+}
+
+func (m *memQuorumClient) SetRequestTimeout(d time.Duration) {}
+
+func (m *memQuorumClient) abort() {
+	m.abortOnce.Do(func() { close(m.aborted) })
+}
+
+func (m *memQuorumClient) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+func (m *memQuorumClient) isClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+func (m *memQuorumClient) contents() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return string(m.data)
+}
+
+func (m *memQuorumClient) setDropResponses(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropResponses = n
+}
+
+func (m *memQuorumClient) setStall(stall chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stall = stall
+}
+
+// dropChainResponse makes the next append lose its response after being
+// forwarded to the given number of replicas after this one.
+func (m *memQuorumClient) dropChainResponse(forwards int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropResponses = 1
+	m.dropForwards = forwards
+}
+
+func (m *memQuorumClient) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// memCluster is a set of in-memory replicas keyed by address. Clients for the
+// same address share the replica's data.
+type memCluster struct {
+	mu       sync.Mutex
+	replicas map[string]*memQuorumClient
+	created  []string // addresses on which the object was created
+}
+
+func newMemCluster() *memCluster {
+	return &memCluster{replicas: make(map[string]*memQuorumClient)}
+}
+
+func (c *memCluster) replica(addr string) *memQuorumClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := c.replicas[addr]
+	if r == nil {
+		r = &memQuorumClient{c: c, aborted: make(chan struct{})}
+		c.replicas[addr] = r
+	}
+	return r
+}
+
+func (c *memCluster) factory(addr string) quorumClient {
+	return c.replica(addr)
+}
+
+// controlOption returns an option that resolves control clients to c.
+func (c *memCluster) controlOption() QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.control = func(addr string) (blobController, error) {
+			return memBlobController{c: c, addr: addr}, nil
+		}
+	}
+}
+
+// memBlobController implements blobController for a replica in a memCluster.
+type memBlobController struct {
+	c    *memCluster
+	addr string
+}
+
+func (m memBlobController) Create(ctx context.Context, id ObjectID) error {
+	m.c.mu.Lock()
+	defer m.c.mu.Unlock()
+	m.c.created = append(m.c.created, m.addr)
+	return nil
+}
+
+func (m memBlobController) Seal(ctx context.Context, id ObjectID) (int64, error) {
+	r := m.c.replica(m.addr)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sealed = true
+	return int64(len(r.data)), nil
+}
+
+func (m memBlobController) Stat(ctx context.Context, id ObjectID) (int64, bool, error) {
+	r := m.c.replica(m.addr)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(len(r.data)), r.sealed, nil
+}
+
+// memSealer records the sizes passed to objectSealer.Seal.
+type memSealer struct {
+	mu    sync.Mutex
+	sizes []int64
+}
+
+func (m *memSealer) Seal(ctx context.Context, objectID []byte, size int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sizes = append(m.sizes, size)
+	return nil
+}
+
+// option returns an option that records sealed sizes with m.
+func (m *memSealer) option() QuorumWriterOption {
+	return func(w *QuorumWriter) {
+		w.controller = m
+	}
+}
+
+func newMemQuorumWriter(
+	t *testing.T, c *memCluster, n int, opts ...QuorumWriterOption,
+) *QuorumWriter {
+	t.Helper()
+	replicas := make([]basaltpb.ReplicaInfo, n)
+	for i := range replicas {
+		replicas[i] = basaltpb.ReplicaInfo{Addr: fmt.Sprintf("addr%d", i)}
+	}
+	w := newQuorumWriterWithFactory(ObjectID{1}, replicas, c.factory, opts...)
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+// TestQuorumWriterReplaceReplica tests replacing a failed replica with a new
+// one that is backfilled and then receives subsequent writes.
+func TestQuorumWriterReplaceReplica(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, c.controlOption())
+	ctx := context.Background()
+
+	if err := w.WriteAndSync(context.Background(), []byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	c.replica("addr2").setErr(errors.New("disk failed"))
+	if err := w.WriteAndSync(context.Background(), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ReplaceReplica(ctx, "addr2", basaltpb.ReplicaInfo{Addr: "addr3"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteAndSync(context.Background(), []byte("!")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WaitForAllReplicas(ctx, w.Offset()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.created) != 1 || c.created[0] != "addr3" {
+		t.Fatalf("expected object to be created on addr3, got %v", c.created)
+	}
+	if got := c.replica("addr3").contents(); got != "hello world!" {
+		t.Fatalf("expected replacement to have %q, got %q", "hello world!", got)
+	}
+	status := w.ReplicaStatus()
+	if status[2].Addr != "addr3" || status[2].DurableOffset != 12 || status[2].Err != nil {
+		t.Fatalf("unexpected status for replacement: %+v", status[2])
+	}
+
+	if err := w.ReplaceReplica(ctx, "addr2", basaltpb.ReplicaInfo{Addr: "addr4"}); err == nil {
+		t.Fatal("expected error replacing unknown replica")
+	}
+}
+
+// TestQuorumWriterReplaceHungReplica tests that replacing a replica that
+// stopped responding aborts its request and stops its worker.
+func TestQuorumWriterReplaceHungReplica(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, c.controlOption())
+	ctx := context.Background()
+
+	stall := make(chan struct{})
+	defer close(stall)
+	c.replica("addr2").setStall(stall)
+	if err := w.WriteAndSync(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ReplaceReplica(ctx, "addr2", basaltpb.ReplicaInfo{Addr: "addr3"}); err != nil {
+		t.Fatal(err)
+	}
+	// The old worker, which closes its client on exit, has exited.
+	if !c.replica("addr2").isClosed() {
+		t.Fatal("expected the replaced replica's worker to have exited")
+	}
+	if err := w.WaitForAllReplicas(ctx, w.Offset()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.replica("addr3").contents(); got != "hello" {
+		t.Fatalf("expected replacement to have %q, got %q", "hello", got)
+	}
+}
+
+// TestQuorumWriterReplaceReplicaRequiresControl tests that replacing a
+// replica fails without a control client resolver.
+func TestQuorumWriterReplaceReplicaRequiresControl(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3)
+	err := w.ReplaceReplica(context.Background(), "addr2", basaltpb.ReplicaInfo{Addr: "addr3"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// TestQuorumWriterContextTimeout tests that WriteAndSync returns when its
+// context expires without quorum, and that the writer remains usable.
+func TestQuorumWriterContextTimeout(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := w.WriteAndSync(ctx, []byte("aaaa"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// The timed out write is still in flight. A later write completes once
+	// a quorum has acknowledged both.
+	reqB := asyncWrite(w, []byte("bbbb"))
+	waitForOffset(t, w, 8)
+	for _, c := range clients[:2] {
+		c.complete(nil)
+		c.complete(nil)
+	}
+	expectResult(t, reqB, nil)
+	clients[2].complete(nil)
+	clients[2].complete(nil)
+}
+
+// TestQuorumWriterSeal tests sealing an object with one replica that failed
+// partway through.
+func TestQuorumWriterSeal(t *testing.T) {
+	c := newMemCluster()
+	var sealer memSealer
+	w := newMemQuorumWriter(t, c, 3, c.controlOption(), sealer.option())
+	ctx := context.Background()
+
+	if err := w.WriteAndSync(ctx, []byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	c.replica("addr2").setErr(errors.New("disk failed"))
+	if err := w.WriteAndSync(ctx, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := w.Seal(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Size != 11 {
+		t.Fatalf("expected size 11, got %d", result.Size)
+	}
+	if len(result.Divergent) != 1 || result.Divergent["addr2"] == nil {
+		t.Fatalf("expected addr2 to be divergent, got %v", result.Divergent)
+	}
+	if len(sealer.sizes) != 1 || sealer.sizes[0] != 11 {
+		t.Fatalf("expected size 11 to be recorded, got %v", sealer.sizes)
+	}
+	for _, addr := range []string{"addr0", "addr1"} {
+		if got := c.replica(addr).contents(); got != "hello world" {
+			t.Fatalf("%s: expected %q, got %q", addr, "hello world", got)
+		}
+	}
+
+	if err := w.WriteAndSync(ctx, []byte("!")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed after seal, got %v", err)
+	}
+}
+
+// TestQuorumWriterSealNoQuorum tests that the size is not recorded when
+// fewer than a quorum of replicas can be sealed.
+func TestQuorumWriterSealNoQuorum(t *testing.T) {
+	c := newMemCluster()
+	var sealer memSealer
+	w := newMemQuorumWriter(t, c, 3, sealer.option())
+	w.control = func(addr string) (blobController, error) {
+		if addr != "addr0" {
+			return nil, errors.Newf("%s unreachable", addr)
+		}
+		return memBlobController{c: c, addr: addr}, nil
+	}
+
+	if err := w.WriteAndSync(context.Background(), []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	result, err := w.Seal(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(result.Divergent) != 2 {
+		t.Fatalf("expected 2 divergent replicas, got %v", result.Divergent)
+	}
+	if len(sealer.sizes) != 0 {
+		t.Fatalf("expected no size to be recorded, got %v", sealer.sizes)
+	}
+}
+
+// TestQuorumWriterSealDrainFailure tests that the writer is closed when Seal
+// fails to drain outstanding writes.
+func TestQuorumWriterSealDrainFailure(t *testing.T) {
+	c := newMemCluster()
+	var sealer memSealer
+	w := newMemQuorumWriter(t, c, 3, sealer.option(), c.controlOption())
+
+	c.replica("addr1").setErr(errors.New("disk full"))
+	c.replica("addr2").setErr(errors.New("disk full"))
+	if err := w.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Seal(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := w.Write([]byte("world")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+// TestQuorumWriterZoneRule tests that acknowledgements from a single zone do
+// not satisfy a rule requiring two zones.
+func TestQuorumWriterZoneRule(t *testing.T) {
+	replicas := []basaltpb.ReplicaInfo{
+		{Addr: "addr0", Zone: "a"},
+		{Addr: "addr1", Zone: "a"},
+		{Addr: "addr2", Zone: "b"},
+	}
+	w, clients := newTestQuorumWriterWithReplicas(t, replicas, WithQuorumRule(MajorityAcrossZones(2)))
+	errReplica := errors.New("replica failed")
+
+	reqA := asyncWrite(w, []byte("aaaa"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	expectPending(t, reqA)
+	clients[2].complete(nil)
+	expectResult(t, reqA, nil)
+
+	// Once zone b fails, the remaining replicas can no longer form a quorum.
+	reqB := asyncWrite(w, []byte("bbbb"))
+	clients[2].complete(errReplica)
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	expectResult(t, reqB, errReplica)
+}
+
+// TestQuorumWriterTiers tests an explicit write quorum restricted to SSD
+// replicas, with an HDD replica written asynchronously.
+func TestQuorumWriterTiers(t *testing.T) {
+	replicas := []basaltpb.ReplicaInfo{
+		{Addr: "ssd0", Tier: basaltpb.StorageTier_STORAGE_TIER_SSD},
+		{Addr: "ssd1"},
+		{Addr: "hdd0", Tier: basaltpb.StorageTier_STORAGE_TIER_HDD},
+	}
+	w, clients := newTestQuorumWriterWithReplicas(t, replicas,
+		WithQuorumWriteQuorum(2), WithQuorumTiers(basaltpb.StorageTier_STORAGE_TIER_SSD))
+	errReplica := errors.New("replica failed")
+
+	// The write completes once both SSD replicas acknowledge, without
+	// waiting for the HDD replica.
+	reqA := asyncWrite(w, []byte("aaaa"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	clients[0].complete(nil)
+	expectPending(t, reqA)
+	clients[1].complete(nil)
+	expectResult(t, reqA, nil)
+	clients[2].complete(nil)
+	waitForDurable(t, w, 2, 4)
+
+	// A failed HDD replica does not affect writes.
+	reqB := asyncWrite(w, []byte("bbbb"))
+	clients[2].complete(errReplica)
+	clients[0].complete(nil)
+	clients[1].complete(nil)
+	expectResult(t, reqB, nil)
+
+	// Without both SSD replicas, the write quorum cannot be reached.
+	reqC := asyncWrite(w, []byte("cccc"))
+	clients[0].complete(nil)
+	clients[1].complete(errReplica)
+	expectResult(t, reqC, errReplica)
+}
+
+// openMemQuorumWriter opens a writer on the first n replicas of c.
+func openMemQuorumWriter(
+	t *testing.T, c *memCluster, n int, offset int64, opts ...QuorumWriterOption,
+) (*QuorumWriter, error) {
+	t.Helper()
+	replicas := make([]basaltpb.ReplicaInfo, n)
+	for i := range replicas {
+		replicas[i] = basaltpb.ReplicaInfo{Addr: fmt.Sprintf("addr%d", i)}
+	}
+	opts = append(opts, c.controlOption())
+	w, err := openQuorumWriterWithFactory(
+		context.Background(), ObjectID{1}, replicas, offset, c.factory, opts...)
+	if err == nil {
+		t.Cleanup(func() { _ = w.Close() })
+	}
+	return w, err
+}
+
+// TestOpenQuorumWriter tests resuming writes to an existing object.
+func TestOpenQuorumWriter(t *testing.T) {
+	for _, offset := range []int64{-1, 6} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			c := newMemCluster()
+			for i := 0; i < 3; i++ {
+				c.replica(fmt.Sprintf("addr%d", i)).data = []byte("hello ")
+			}
+			w, err := openMemQuorumWriter(t, c, 3, offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.Offset() != 6 {
+				t.Fatalf("expected offset 6, got %d", w.Offset())
+			}
+			if err := w.WriteAndSync(context.Background(), []byte("world")); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WaitForAllReplicas(context.Background(), w.Offset()); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				if got := c.replica(fmt.Sprintf("addr%d", i)).contents(); got != "hello world" {
+					t.Fatalf("addr%d: expected %q, got %q", i, "hello world", got)
+				}
+			}
+		})
+	}
+}
+
+// TestOpenQuorumWriterValidation tests that a writer is not opened unless all
+// replicas hold the expected data and are unsealed.
+func TestOpenQuorumWriterValidation(t *testing.T) {
+	setup := func(sizes ...int) *memCluster {
+		c := newMemCluster()
+		for i, size := range sizes {
+			c.replica(fmt.Sprintf("addr%d", i)).data = make([]byte, size)
+		}
+		return c
+	}
+
+	if _, err := openMemQuorumWriter(t, setup(6, 6, 6), 3, 4); !errors.Is(err, ErrReplicasDisagree) {
+		t.Fatalf("expected ErrReplicasDisagree for wrong offset, got %v", err)
+	}
+	if _, err := openMemQuorumWriter(t, setup(6, 4, 6), 3, -1); !errors.Is(err, ErrReplicasDisagree) {
+		t.Fatalf("expected ErrReplicasDisagree for differing sizes, got %v", err)
+	}
+
+	c := setup(6, 6, 6)
+	c.replica("addr1").sealed = true
+	if _, err := openMemQuorumWriter(t, c, 3, -1); !errors.Is(err, ErrSealed) {
+		t.Fatalf("expected ErrSealed, got %v", err)
+	}
+
+	replicas := []basaltpb.ReplicaInfo{{Addr: "addr0"}}
+	_, err := openQuorumWriterWithFactory(context.Background(), ObjectID{1}, replicas, 0, c.factory)
+	if err == nil {
+		t.Fatal("expected error without control client resolver")
+	}
+}
+
+// TestQuorumWriterQueueLimit tests that a replica whose queue exceeds the
+// limit is ejected without affecting the quorum of the others.
+func TestQuorumWriterQueueLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		maxBytes   int64
+		maxEntries int
+	}{
+		{"bytes", 8, 0},
+		{"entries", 0, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			type ejection struct {
+				addr string
+				err  error
+			}
+			ejections := make(chan ejection, 1)
+			w, clients := newTestQuorumWriter(t, 3,
+				WithQuorumReplicaQueueLimit(tc.maxBytes, tc.maxEntries),
+				WithQuorumEjectionCallback(func(addr string, err error) {
+					ejections <- ejection{addr, err}
+				}))
+
+			// Replica 2 gets stuck on the first write.
+			reqA := asyncWrite(w, []byte("aaaa"))
+			for _, c := range clients {
+				c.waitForStart(t)
+			}
+			clients[0].complete(nil)
+			clients[1].complete(nil)
+			expectResult(t, reqA, nil)
+
+			reqB := asyncWrite(w, []byte("bbbb"))
+			waitForOffset(t, w, 8)
+			select {
+			case e := <-ejections:
+				t.Fatalf("unexpected ejection: %+v", e)
+			default:
+			}
+			reqC := asyncWrite(w, []byte("cccc"))
+			waitForOffset(t, w, 12)
+
+			select {
+			case e := <-ejections:
+				if e.addr != "addr2" || !errors.Is(e.err, errReplicaQueueLimitExceeded) {
+					t.Fatalf("unexpected ejection: %+v", e)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for ejection")
+			}
+			if status := w.ReplicaStatus()[2]; !status.Ejected || status.Err == nil {
+				t.Fatalf("expected addr2 to be ejected, got %+v", status)
+			}
+
+			for _, c := range clients[:2] {
+				c.complete(nil)
+				c.complete(nil)
+			}
+			expectResult(t, reqB, nil)
+			expectResult(t, reqC, nil)
+			clients[2].complete(nil)
+		})
+	}
+}
+
+// TestQuorumWriterQueueLimitAllReplicas tests that when every replica is at
+// the queue limit, a write waits instead of ejecting them, until a replica
+// falls behind the committed offset and can be spared.
+func TestQuorumWriterQueueLimitAllReplicas(t *testing.T) {
+	ejections := make(chan string, 3)
+	w, clients := newTestQuorumWriter(t, 3,
+		WithQuorumReplicaQueueLimit(0, 2),
+		WithQuorumEjectionCallback(func(addr string, err error) {
+			ejections <- addr
+		}))
+
+	reqA := asyncWrite(w, []byte("aaaa"))
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	asyncWrite(w, []byte("bbbb"))
+	waitForOffset(t, w, 8)
+
+	// Every replica has A and B queued, so C must wait. Once replica 0 has
+	// written A, A is still not committed, so no other replica can be
+	// spared and C keeps waiting.
+	asyncWrite(w, []byte("cccc"))
+	clients[0].complete(nil)
+	waitForDurable(t, w, 0, 4)
+	time.Sleep(50 * time.Millisecond)
+	w.mu.Lock()
+	offset := w.offset
+	w.mu.Unlock()
+	if offset != 8 {
+		t.Fatalf("expected C to wait for room in the queues, offset is %d", offset)
+	}
+	select {
+	case addr := <-ejections:
+		t.Fatalf("unexpected ejection of %s", addr)
+	default:
+	}
+
+	// Once A is committed, replica 2 is behind and can be spared.
+	clients[1].complete(nil)
+	expectResult(t, reqA, nil)
+	waitForOffset(t, w, 12)
+	select {
+	case addr := <-ejections:
+		if addr != "addr2" {
+			t.Fatalf("unexpected ejection of %s", addr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for ejection")
+	}
+	for i, status := range w.ReplicaStatus()[:2] {
+		if status.Ejected {
+			t.Fatalf("replica %d ejected: %+v", i, status)
+		}
+	}
+}
+
+// TestQuorumWriterQueueLimitLargeWrite tests that a write larger than the
+// queue size limit is rejected without ejecting any replica.
+func TestQuorumWriterQueueLimitLargeWrite(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, WithQuorumReplicaQueueLimit(8, 0))
+	ctx := context.Background()
+
+	err := w.WriteAndSync(ctx, []byte("0123456789abcdef"))
+	if !errors.Is(err, errWriteExceedsQueueLimit) {
+		t.Fatalf("expected errWriteExceedsQueueLimit, got %v", err)
+	}
+	for i, status := range w.ReplicaStatus() {
+		if status.Ejected || status.Err != nil {
+			t.Fatalf("replica %d failed: %+v", i, status)
+		}
+	}
+	if err := w.WriteAndSync(ctx, []byte("01234567")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestQuorumWriterFailureReporter tests that an ejected replica is reported
+// to the controller.
+func TestQuorumWriterFailureReporter(t *testing.T) {
+	c := newMemCluster()
+	reports := make(chanFailureSink, 10)
+	reporter := newFailureReporter(reports, NopLogger, time.Minute)
+	w := newMemQuorumWriter(t, c, 3, WithQuorumFailureReporter(reporter))
+
+	c.replica("addr1").setErr(ErrIOError)
+	if err := w.WriteAndSync(context.Background(), []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	expectReport(t, reports, failureReport{
+		objectID: ObjectID{1},
+		addr:     "addr1",
+		kind:     basaltpb.ReplicaFailureKind_REPLICA_FAILURE_KIND_IO_ERROR,
+	})
+}
+
+// waitForSyncRequested waits until every replica has been asked to sync up
+// to offset.
+func waitForSyncRequested(t *testing.T, w *QuorumWriter, offset int64) {
+	t.Helper()
+	waitFor(t, func() error {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for _, rw := range w.workers {
+			rw.mu.Lock()
+			requested := rw.syncTo >= offset
+			rw.mu.Unlock()
+			if !requested {
+				return errors.Newf("timeout waiting for sync to %d to be requested", offset)
+			}
+		}
+		return nil
+	})
+}
+
+// TestQuorumWriterGroupCommit tests that writes queued behind an in-flight
+// request are coalesced, and that concurrent syncs share a single synced
+// request.
+func TestQuorumWriterGroupCommit(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3)
+
+	if err := w.Write([]byte("aaaa")); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range clients {
+		c.waitForStart(t)
+	}
+	if err := w.Write([]byte("bbbb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]byte("cccc")); err != nil {
+		t.Fatal(err)
+	}
+	syncs := make([]chan error, 3)
+	for i := range syncs {
+		syncs[i] = make(chan error, 1)
+		go func() { syncs[i] <- w.Sync(context.Background()) }()
+	}
+	waitForSyncRequested(t, w, 12)
+
+	for _, c := range clients {
+		c.complete(nil)
+	}
+	for _, ch := range syncs {
+		expectPending(t, ch)
+	}
+	for _, c := range clients {
+		c.complete(nil)
+	}
+	for _, ch := range syncs {
+		expectResult(t, ch, nil)
+	}
+
+	want := []mockCall{
+		{sync: false, offset: 0, data: "aaaa"},
+		{sync: true, offset: 4, data: "bbbbcccc"},
+	}
+	for i, c := range clients {
+		if got := c.recordedCalls(); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("replica %d: expected calls %v, got %v", i, want, got)
+		}
+	}
+	if s := w.ReplicaStatus()[0]; s.WrittenOffset != 12 || s.DurableOffset != 12 {
+		t.Fatalf("unexpected status: %+v", s)
+	}
+}
+
+// TestQuorumWriterSyncWithoutData tests that a sync requested after all
+// writes have been sent is sent as a request without data at offset 0, like
+// BlobDataClient.Sync.
+func TestQuorumWriterSyncWithoutData(t *testing.T) {
+	w, clients := newTestQuorumWriter(t, 3)
+
+	if err := w.Write([]byte("aaaa")); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range clients {
+		c.waitForStart(t)
+		c.complete(nil)
+	}
+	if s := w.ReplicaStatus()[0]; s.DurableOffset != 0 {
+		t.Fatalf("expected write not to be durable, got %+v", s)
+	}
+
+	ch := make(chan error, 1)
+	go func() { ch <- w.Sync(context.Background()) }()
+	for _, c := range clients[:2] {
+		c.complete(nil)
+	}
+	expectResult(t, ch, nil)
+	clients[2].complete(nil)
+
+	want := []mockCall{
+		{sync: false, offset: 0, data: "aaaa"},
+		{sync: true, offset: 0, data: ""},
+	}
+	if got := clients[0].recordedCalls(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected calls %v, got %v", want, got)
+	}
+}
+
+// TestQuorumWriterWriteSync tests Write and Sync against in-memory replicas.
+func TestQuorumWriterWriteSync(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3)
+	ctx := context.Background()
+
+	for _, s := range []string{"hello", " ", "world"} {
+		if err := w.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Syncing again with nothing new written returns immediately.
+	if err := w.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WaitForAllReplicas(ctx, w.Offset()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if got := c.replica(fmt.Sprintf("addr%d", i)).contents(); got != "hello world" {
+			t.Fatalf("addr%d: expected %q, got %q", i, "hello world", got)
+		}
+	}
+
+	_ = w.Close()
+	if err := w.Write([]byte("!")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if err := w.Sync(ctx); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+// TestQuorumWriterChainReplication tests that in chain replication mode writes
+// are sent only to the head of the chain and reach every replica.
+func TestQuorumWriterChainReplication(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, WithQuorumChainReplication())
+	ctx := context.Background()
+
+	if err := w.WriteAndSync(ctx, []byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		addr := fmt.Sprintf("addr%d", i)
+		if got := c.replica(addr).contents(); got != "hello world" {
+			t.Fatalf("%s: expected %q, got %q", addr, "hello world", got)
+		}
+		if n := c.replica(addr).chainRequests(); (i == 0) != (n > 0) {
+			t.Fatalf("%s: unexpected %d requests from the client", addr, n)
+		}
+	}
+	for _, status := range w.ReplicaStatus() {
+		if status.DurableOffset != 11 || status.Err != nil {
+			t.Fatalf("unexpected replica status %+v", status)
+		}
+	}
+}
+
+// TestQuorumWriterChainReconfiguration tests that failed replicas are dropped
+// from the chain and the replicas after them are caught up.
+func TestQuorumWriterChainReconfiguration(t *testing.T) {
+	c := newMemCluster()
+	ejected := make(chan string, 3)
+	w := newMemQuorumWriter(t, c, 5, WithQuorumChainReplication(),
+		WithQuorumEjectionCallback(func(addr string, err error) { ejected <- addr }))
+	ctx := context.Background()
+
+	if err := w.WriteAndSync(ctx, []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed middle replica is dropped; the tail is caught up directly.
+	c.replica("addr1").setErr(errors.New("disk failed"))
+	if err := w.WriteAndSync(ctx, []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if addr := <-ejected; addr != "addr1" {
+		t.Fatalf("expected addr1 to be ejected, got %s", addr)
+	}
+	for _, addr := range []string{"addr0", "addr2", "addr3", "addr4"} {
+		if got := c.replica(addr).contents(); got != "ab" {
+			t.Fatalf("%s: expected %q, got %q", addr, "ab", got)
+		}
+	}
+	if n := c.replica("addr2").chainRequests(); n != 1 {
+		t.Fatalf("expected addr2 to be caught up with 1 request, got %d", n)
+	}
+
+	// A failed head is dropped and the next replica becomes the head.
+	c.replica("addr0").setErr(errors.New("disk failed"))
+	if err := w.WriteAndSync(ctx, []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if addr := <-ejected; addr != "addr0" {
+		t.Fatalf("expected addr0 to be ejected, got %s", addr)
+	}
+	if err := w.WriteAndSync(ctx, []byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{"addr2", "addr3", "addr4"} {
+		if got := c.replica(addr).contents(); got != "abcd" {
+			t.Fatalf("%s: expected %q, got %q", addr, "abcd", got)
+		}
+	}
+
+	// With only three of five replicas left, the next failure loses quorum.
+	c.replica("addr3").setErr(errors.New("disk failed"))
+	if err := w.WriteAndSync(ctx, []byte("e")); err == nil {
+		t.Fatal("expected write to fail without a quorum")
+	}
+	status := w.ReplicaStatus()
+	for i, ejected := range []bool{true, true, false, true, false} {
+		if status[i].Ejected != ejected {
+			t.Fatalf("replica %d: expected ejected=%t, got %+v", i, ejected, status[i])
+		}
+	}
+	if status[2].DurableOffset != 5 {
+		t.Fatalf("expected addr2 to be durable up to 5, got %+v", status[2])
+	}
+}
+
+// TestQuorumWriterChainReconnect tests that when the connection to the head of
+// the chain drops after a write was forwarded, the writer reconnects and
+// reconciles the replicas rather than ejecting those that already hold the
+// write.
+func TestQuorumWriterChainReconnect(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, WithQuorumChainReplication(),
+		WithQuorumReconnectBackoff(time.Millisecond, time.Millisecond))
+	ctx := context.Background()
+
+	// The write reaches every replica before the response is lost.
+	c.replica("addr0").dropChainResponse(2)
+	if err := w.WriteAndSync(ctx, []byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	// The write reaches the middle of the chain but not its tail, which is
+	// caught up directly.
+	c.replica("addr0").dropChainResponse(1)
+	if err := w.WriteAndSync(ctx, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteAndSync(ctx, []byte("!")); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, status := range w.ReplicaStatus() {
+		if status.DurableOffset != 12 || status.Err != nil || status.Ejected {
+			t.Fatalf("replica %d: unexpected status %+v", i, status)
+		}
+		if got := c.replica(status.Addr).contents(); got != "hello world!" {
+			t.Fatalf("replica %d: expected %q, got %q", i, "hello world!", got)
+		}
+	}
+}
+
+// TestQuorumWriterChainTiers tests that in chain replication mode with
+// quorum tiers, the chain is made up of the replicas in those tiers, and
+// writes do not wait for the others.
+func TestQuorumWriterChainTiers(t *testing.T) {
+	c := newMemCluster()
+	replicas := []basaltpb.ReplicaInfo{
+		{Addr: "addr0", Tier: basaltpb.StorageTier_STORAGE_TIER_SSD},
+		{Addr: "addr1", Tier: basaltpb.StorageTier_STORAGE_TIER_HDD},
+		{Addr: "addr2", Tier: basaltpb.StorageTier_STORAGE_TIER_SSD},
+	}
+	w := newQuorumWriterWithFactory(ObjectID{1}, replicas, c.factory,
+		WithQuorumChainReplication(), WithQuorumTiers(basaltpb.StorageTier_STORAGE_TIER_SSD))
+	defer func() { _ = w.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stall := make(chan struct{})
+	c.replica("addr1").setStall(stall)
+	if err := w.WriteAndSync(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.replica("addr2").contents(); got != "hello" {
+		t.Fatalf("addr2: expected %q, got %q", "hello", got)
+	}
+	if got := c.replica("addr1").contents(); got != "" {
+		t.Fatalf("addr1: expected no data yet, got %q", got)
+	}
+
+	close(stall)
+	if err := w.WaitForAllReplicas(ctx, w.Offset()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.replica("addr1").contents(); got != "hello" {
+		t.Fatalf("addr1: expected %q, got %q", "hello", got)
+	}
+	if n := c.replica("addr1").chainRequests(); n != 0 {
+		t.Fatalf("expected addr1 to be written outside the chain, got %d chained appends", n)
+	}
+}
+
+// TestQuorumWriterChainReplaceReplica tests that ReplaceReplica is rejected
+// in chain replication mode.
+func TestQuorumWriterChainReplaceReplica(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3, c.controlOption(), WithQuorumChainReplication())
+	err := w.ReplaceReplica(context.Background(), "addr0", basaltpb.ReplicaInfo{Addr: "addr3"})
+	if err == nil {
+		t.Fatal("expected ReplaceReplica to fail in chain replication mode")
+	}
+}
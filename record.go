@@ -0,0 +1,222 @@
+package basaltclient
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+)
+
+// RecordHeaderSize is the size of the header framing each record.
+// Checksum(4) + Length(4) + Seq(8) = 16
+const RecordHeaderSize = 16
+
+// MaxRecordSize is the maximum size of a record's data.
+const MaxRecordSize = math.MaxUint32
+
+// crc32cTable is the CRC-32C (Castagnoli) table used for record checksums.
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrCorruptRecord is returned by RecordReader when it reaches a record that
+// is torn, because the object ends partway through it, or corrupt, because
+// its checksum or sequence number does not match.
+var ErrCorruptRecord = errors.New("torn or corrupt record")
+
+// RecordWriter writes a log of framed records to a QuorumWriter. Each record
+// is prefixed with its length, a sequence number, and a CRC-32C checksum
+// covering both and the data, so that a RecordReader can tell valid records
+// from torn or corrupt ones when the object is read back.
+//
+// Sequence numbers increase by one with each record, in the order the records
+// are placed in the object. A RecordWriter must be the only writer of its
+// QuorumWriter; mixing records with raw writes makes the object unreadable by
+// RecordReader.
+//
+// RecordWriter is safe for concurrent use.
+type RecordWriter struct {
+	w *QuorumWriter
+
+	mu  sync.Mutex // orders sequence numbers with offsets
+	seq uint64     // sequence number of the next record
+}
+
+// NewRecordWriter creates a RecordWriter writing to w, numbering records
+// starting at seq. When resuming a log opened with OpenQuorumWriter, seq
+// should follow the sequence number of its last record.
+func NewRecordWriter(w *QuorumWriter, seq uint64) *RecordWriter {
+	return &RecordWriter{w: w, seq: seq}
+}
+
+// WriteRecord writes data as the next record and waits until a quorum of
+// replicas have durably written it and every record before it, like
+// QuorumWriter.WriteAndSync. It returns the record's sequence number.
+func (rw *RecordWriter) WriteRecord(ctx context.Context, data []byte) (uint64, error) {
+	seq, end, err := rw.enqueue(ctx, data, true /* sync */)
+	if err != nil {
+		return 0, err
+	}
+	return seq, rw.w.waitForQuorum(ctx, end)
+}
+
+// AppendRecord queues data as the next record without waiting for it to be
+// written, like QuorumWriter.Write, and returns the record's sequence number.
+// The record is not durable until a subsequent Sync or WriteRecord returns
+// successfully. Unlike QuorumWriter.Write, the caller may modify data once
+// AppendRecord returns.
+func (rw *RecordWriter) AppendRecord(data []byte) (uint64, error) {
+	seq, _, err := rw.enqueue(context.Background(), data, false /* sync */)
+	return seq, err
+}
+
+// Sync waits until a quorum of replicas have durably written every record
+// written so far. See QuorumWriter.Sync.
+func (rw *RecordWriter) Sync(ctx context.Context) error {
+	return rw.w.Sync(ctx)
+}
+
+// enqueue frames data with the next sequence number and queues it to the
+// writer, returning the sequence number and the end offset of the record.
+func (rw *RecordWriter) enqueue(
+	ctx context.Context, data []byte, sync bool,
+) (uint64, int64, error) {
+	if uint64(len(data)) > MaxRecordSize {
+		return 0, 0, errors.Newf("record too large: %d > %d", uint64(len(data)), uint64(MaxRecordSize))
+	}
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	seq := rw.seq
+	end, err := rw.w.enqueueWrite(ctx, encodeRecord(seq, data), sync)
+	if err != nil {
+		return 0, 0, err
+	}
+	rw.seq++
+	return seq, end, nil
+}
+
+// encodeRecord returns data framed as the record with sequence number seq.
+//
+// Wire format: Checksum(4) + Length(4) + Seq(8) + Data, where the checksum is
+// the CRC-32C of everything following it.
+func encodeRecord(seq uint64, data []byte) []byte {
+	buf := make([]byte, RecordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(buf[4:8], uint32(len(data)))
+	binary.BigEndian.PutUint64(buf[8:16], seq)
+	copy(buf[RecordHeaderSize:], data)
+	binary.BigEndian.PutUint32(buf[0:4], crc32.Checksum(buf[4:], crc32cTable))
+	return buf
+}
+
+// Record is a record read by RecordReader.
+type Record struct {
+	// Seq is the record's sequence number.
+	Seq uint64
+	// Offset is the offset of the record's header in the object.
+	Offset int64
+	// Data is the record's data.
+	Data []byte
+}
+
+// RecordReader reads the records written by a RecordWriter, validating each
+// one. It reads from any io.ReaderAt, such as the RecoveredObject returned by
+// RecoverQuorumPrefix.
+//
+// Reading stops at the first record that is torn or corrupt, or whose
+// sequence number does not follow the previous record's; everything before
+// it is returned intact, and Offset reports where the valid records end.
+type RecordReader struct {
+	r      io.ReaderAt
+	size   int64
+	offset int64  // offset of the next record
+	seq    uint64 // sequence number of the last record read
+	read   bool   // whether any record has been read
+	err    error  // sticky error once reading has stopped
+	hdr    [RecordHeaderSize]byte
+}
+
+// NewRecordReader creates a RecordReader over the first size bytes of r.
+func NewRecordReader(r io.ReaderAt, size int64) *RecordReader {
+	return &RecordReader{r: r, size: size}
+}
+
+// Next returns the next record. It returns io.EOF once every record has been
+// read and the log ends cleanly, or an error marked with ErrCorruptRecord if
+// it reaches a torn or corrupt record; other errors are returned if reading
+// from the underlying reader fails. Once Next returns an error, it returns
+// the same error on every subsequent call.
+//
+// The returned Data is newly allocated and may be retained by the caller.
+func (rr *RecordReader) Next() (Record, error) {
+	if rr.err != nil {
+		return Record{}, rr.err
+	}
+	rec, err := rr.next()
+	if err != nil {
+		rr.err = err
+		return Record{}, err
+	}
+	rr.offset += RecordHeaderSize + int64(len(rec.Data))
+	rr.seq = rec.Seq
+	rr.read = true
+	return rec, nil
+}
+
+func (rr *RecordReader) next() (Record, error) {
+	remaining := rr.size - rr.offset
+	if remaining == 0 {
+		return Record{}, io.EOF
+	}
+	if remaining < RecordHeaderSize {
+		return Record{}, rr.corruptf("torn record header (%d bytes)", remaining)
+	}
+	if err := rr.readAt(rr.hdr[:], rr.offset); err != nil {
+		return Record{}, err
+	}
+	checksum := binary.BigEndian.Uint32(rr.hdr[0:4])
+	length := int64(binary.BigEndian.Uint32(rr.hdr[4:8]))
+	seq := binary.BigEndian.Uint64(rr.hdr[8:16])
+	if length > remaining-RecordHeaderSize {
+		return Record{}, rr.corruptf("torn record: length %d exceeds the %d bytes remaining",
+			length, remaining-RecordHeaderSize)
+	}
+	data := make([]byte, length)
+	if err := rr.readAt(data, rr.offset+RecordHeaderSize); err != nil {
+		return Record{}, err
+	}
+	crc := crc32.Update(crc32.Checksum(rr.hdr[4:], crc32cTable), crc32cTable, data)
+	if crc != checksum {
+		return Record{}, rr.corruptf("checksum mismatch: %08x != %08x", crc, checksum)
+	}
+	if rr.read && seq != rr.seq+1 {
+		return Record{}, rr.corruptf("sequence number %d does not follow %d", seq, rr.seq)
+	}
+	return Record{Seq: seq, Offset: rr.offset, Data: data}, nil
+}
+
+// readAt fills p from the underlying reader at off.
+func (rr *RecordReader) readAt(p []byte, off int64) error {
+	n, err := rr.r.ReadAt(p, off)
+	if n == len(p) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return errors.Wrapf(err, "reading %d bytes at offset %d", len(p), off)
+}
+
+// corruptf returns an error marked with ErrCorruptRecord for the record at
+// the current offset.
+func (rr *RecordReader) corruptf(format string, args ...any) error {
+	err := errors.Newf(format, args...)
+	return errors.Mark(errors.Wrapf(err, "record at offset %d", rr.offset), ErrCorruptRecord)
+}
+
+// Offset returns the end offset of the last valid record read, which is
+// where the records stop if Next has returned an error.
+func (rr *RecordReader) Offset() int64 {
+	return rr.offset
+}
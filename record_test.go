@@ -0,0 +1,150 @@
+package basaltclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+// writeTestRecords writes records to a new writer on c and returns the
+// contents of the first replica.
+func writeTestRecords(t *testing.T, c *memCluster, seq uint64, records ...string) []byte {
+	t.Helper()
+	w := newMemQuorumWriter(t, c, 3)
+	rw := NewRecordWriter(w, seq)
+	for i, rec := range records {
+		got, err := rw.WriteRecord(context.Background(), []byte(rec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != seq+uint64(i) {
+			t.Fatalf("expected sequence number %d, got %d", seq+uint64(i), got)
+		}
+	}
+	if err := w.WaitForAllReplicas(context.Background(), w.Offset()); err != nil {
+		t.Fatal(err)
+	}
+	return []byte(c.replica("addr0").contents())
+}
+
+// readTestRecords reads records from buf until Next fails, returning the
+// records' data and the error.
+func readTestRecords(t *testing.T, buf []byte) (*RecordReader, []string, error) {
+	t.Helper()
+	rr := NewRecordReader(bytes.NewReader(buf), int64(len(buf)))
+	var records []string
+	for {
+		rec, err := rr.Next()
+		if err != nil {
+			return rr, records, err
+		}
+		if rec.Seq != uint64(len(records))+1 {
+			t.Fatalf("expected sequence number %d, got %d", len(records)+1, rec.Seq)
+		}
+		records = append(records, string(rec.Data))
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	records := []string{"first", "", "third", string(bytes.Repeat([]byte("x"), 100000))}
+	buf := writeTestRecords(t, newMemCluster(), 1, records...)
+
+	rr, got, err := readTestRecords(t, buf)
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(records) {
+		t.Fatalf("expected %d records, got %d", len(records), len(got))
+	}
+	if rr.Offset() != int64(len(buf)) {
+		t.Fatalf("expected offset %d, got %d", len(buf), rr.Offset())
+	}
+	if _, err := rr.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF again, got %v", err)
+	}
+}
+
+func TestRecordReaderStopsAtBadRecord(t *testing.T) {
+	buf := writeTestRecords(t, newMemCluster(), 1, "aaaa", "bbbb", "cccc")
+	recordLen := RecordHeaderSize + 4
+	end := int64(2 * recordLen) // end of the second record
+
+	// The third record from another log with a different sequence number.
+	stale := writeTestRecords(t, newMemCluster(), 7, "aaaa", "bbbb", "cccc")
+
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{"torn header", buf[:end+RecordHeaderSize-1]},
+		{"torn data", buf[:len(buf)-1]},
+		{"corrupt data", func() []byte {
+			b := bytes.Clone(buf)
+			b[len(b)-1] ^= 0xFF
+			return b
+		}()},
+		{"corrupt length", func() []byte {
+			b := bytes.Clone(buf)
+			b[end+4] ^= 0x01
+			return b
+		}()},
+		{"sequence gap", append(bytes.Clone(buf[:end]), stale[end:]...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, got, err := readTestRecords(t, tt.buf)
+			if !errors.Is(err, ErrCorruptRecord) {
+				t.Fatalf("expected ErrCorruptRecord, got %v", err)
+			}
+			if len(got) != 2 || got[0] != "aaaa" || got[1] != "bbbb" {
+				t.Fatalf("expected the first two records, got %q", got)
+			}
+			if rr.Offset() != end {
+				t.Fatalf("expected offset %d, got %d", end, rr.Offset())
+			}
+			if _, err2 := rr.Next(); err2 != err {
+				t.Fatalf("expected the same error again, got %v", err2)
+			}
+		})
+	}
+}
+
+// TestRecordWriterConcurrent tests that sequence numbers follow the order of
+// records in the object when records are appended concurrently.
+func TestRecordWriterConcurrent(t *testing.T) {
+	c := newMemCluster()
+	w := newMemQuorumWriter(t, c, 3)
+	rw := NewRecordWriter(w, 1)
+
+	const n = 100
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := rw.AppendRecord([]byte(fmt.Sprint(i))); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := rw.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WaitForAllReplicas(context.Background(), w.Offset()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, got, err := readTestRecords(t, []byte(c.replica("addr0").contents()))
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if len(got) != n {
+		t.Fatalf("expected %d records, got %d", n, len(got))
+	}
+}
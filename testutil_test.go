@@ -0,0 +1,23 @@
+package basaltclient
+
+import (
+	"testing"
+	"time"
+)
+
+// waitFor polls fn until it returns nil, failing the test with the last
+// error fn returned if that takes longer than 5 seconds.
+func waitFor(t *testing.T, fn func() error) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := fn()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}